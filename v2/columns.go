@@ -0,0 +1,86 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package monetdb
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/MonetDB/MonetDB-Go/v2/mapi"
+)
+
+// Column contains the values of one column of a block of rows, as a slice
+// of the Go type of the column. See FetchColumns.
+type Column = mapi.Column
+
+// ColumnBlock is a block of rows of a result set, stored per column.
+type ColumnBlock = mapi.ColumnBlock
+
+// ColumnKind is the Go type of the values of a Column.
+type ColumnKind = mapi.ColumnKind
+
+// NullBitmap marks the rows of a column that are NULL.
+type NullBitmap = mapi.NullBitmap
+
+// The kinds of a Column. Integer types are stored as int64, except hugeint
+// which is stored as *big.Int, floating point types as float64, decimal
+// types as Decimal, temporal types as time.Time and blobs as []byte. The
+// values of all other types are stored as text.
+const (
+	KindString  = mapi.KindString
+	KindInt64   = mapi.KindInt64
+	KindFloat64 = mapi.KindFloat64
+	KindBool    = mapi.KindBool
+	KindTime    = mapi.KindTime
+	KindBytes   = mapi.KindBytes
+	KindBigInt  = mapi.KindBigInt
+	KindDecimal = mapi.KindDecimal
+)
+
+// FetchColumns returns at most n rows, starting at the position of the
+// cursor, stored per column, and moves the cursor to the row after them.
+// The values are converted directly from the reply of the server into the
+// slices of the columns, without the conversion to driver.Value that Next
+// does. When the cursor is after the last row, io.EOF is returned.
+//
+// The rows are fetched with a single request, unless they were already
+// received. Use Conn.QueryCursor to get the Rows.
+func (r *Rows) FetchColumns(n int) (*ColumnBlock, error) {
+	if !r.active {
+		return nil, fmt.Errorf("monetdb: rows closed")
+	}
+	if n <= 0 {
+		return nil, fmt.Errorf("monetdb: invalid number of rows %d", n)
+	}
+	if r.rowNum >= r.rowCount {
+		return nil, io.EOF
+	}
+	if err := r.ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	start := r.rowNum
+	end := min(r.rowCount, start+n)
+	var tuples []string
+	if r.buffered(start) && r.buffered(end-1) && r.tuples != nil {
+		tuples = r.tuples[start-r.offset : end-r.offset]
+	} else {
+		res, err := r.mapiDo(r.ctx, start, end-start)
+		if err != nil {
+			return nil, err
+		}
+		tuples = res.Tuples()
+		if len(tuples) != end-start {
+			return nil, fmt.Errorf("monetdb: unexpected number of rows %d, expected: %d", len(tuples), end-start)
+		}
+	}
+
+	cols, err := mapi.DecodeColumns(r.schema, tuples)
+	if err != nil {
+		return nil, err
+	}
+	r.rowNum = end
+	return &ColumnBlock{Offset: start, Len: len(tuples), Columns: cols}, nil
+}
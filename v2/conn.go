@@ -8,6 +8,7 @@ import (
 	"context"
 	"database/sql"
 	"database/sql/driver"
+	"errors"
 	"fmt"
 	"time"
 
@@ -17,6 +18,12 @@ import (
 type Conn struct {
 	mapi *mapi.MapiConn
 	timezone *time.Location
+	// bad is set when the communication with the server failed. The
+	// connection can not be used anymore and is removed from the pool.
+	bad bool
+	session session
+	// tx is the active transaction of the connection
+	tx *Tx
 }
 
 func newConn(name string) (*Conn, error) {
@@ -37,10 +44,35 @@ func newConn(name string) (*Conn, error) {
 
 	conn.mapi = m
 	m.SetSizeHeader(true)
+	conn.session.defaultAutoCommit = m.AutoCommit()
+	conn.session.autoCommit = m.AutoCommit()
+	conn.session.inTransaction = !m.AutoCommit()
 	conn.setServerTimezone()
+	conn.session.tracking = true
 	return conn, nil
 }
 
+// ConnStatus is the transaction state of a connection, as it was last
+// reported by the server.
+type ConnStatus struct {
+	// AutoCommit reports whether autocommit is enabled for the session. It
+	// is disabled with the "autocommit=false" option in the DSN, and while
+	// a transaction is running that was started with START TRANSACTION.
+	AutoCommit bool
+	// InTransaction reports whether the session is in a transaction. When
+	// autocommit is disabled, the session is always in a transaction.
+	InTransaction bool
+}
+
+// Status returns the transaction state of the connection. Use sql.Conn.Raw
+// to call this function.
+func (c *Conn) Status() ConnStatus {
+	return ConnStatus{
+		AutoCommit:    c.session.autoCommit,
+		InTransaction: c.session.inTransaction,
+	}
+}
+
 func (c *Conn) setServerTimezone() error {
 	tz, err := time.LoadLocation(c.timezone.String())
 	if err != nil {
@@ -51,6 +83,14 @@ func (c *Conn) setServerTimezone() error {
 	}
 	_, offset := time.Now().Zone()
 
+	query := timezoneQuery(offset)
+	err = executeStmt(c, query)
+	return err
+}
+
+// timezoneQuery returns the statement that sets the time zone of the
+// session to the offset in seconds east of UTC.
+func timezoneQuery(offset int) string {
 	hours := int(offset / 3600)
 	remaining := offset - 3600 * hours
 	minutes := int(remaining / 60)
@@ -58,8 +98,46 @@ func (c *Conn) setServerTimezone() error {
 	if minutes < 0 {
 		minutes = -1 * minutes
 	}
-	query := fmt.Sprintf("SET TIME ZONE INTERVAL '%+03d:%02d' HOUR TO MINUTE;", hours, minutes)
-	err = executeStmt(c, query)
+	sign := "+"
+	if offset < 0 {
+		sign = "-"
+		hours = -1 * hours
+	}
+	return fmt.Sprintf("SET TIME ZONE INTERVAL '%s%02d:%02d' HOUR TO MINUTE;", sign, hours, minutes)
+}
+
+// checkErr marks the connection as bad when err is caused by a failure of the
+// network connection. When the command did not reach the server,
+// driver.ErrBadConn is returned, so the sql package can safely retry it on
+// another connection.
+func (c *Conn) checkErr(err error) error {
+	var connErr *mapi.ConnError
+	if errors.As(err, &connErr) {
+		c.bad = true
+		if !connErr.Sent {
+			return driver.ErrBadConn
+		}
+	}
+	return err
+}
+
+// IsValid is called by the sql package before the connection is reused.
+func (c *Conn) IsValid() bool {
+	return c.mapi != nil && !c.bad
+}
+
+// Ping verifies that the server can still be reached.
+func (c *Conn) Ping(ctx context.Context) error {
+	if !c.IsValid() {
+		return driver.ErrBadConn
+	}
+	stmt := newStmt(c, "SELECT 1", false)
+	_, err := stmt.ExecContext(ctx, nil)
+	defer stmt.Close()
+	if c.bad {
+		// A ping has no side effects, so it is always safe to retry it
+		return driver.ErrBadConn
+	}
 	return err
 }
 
@@ -69,13 +147,31 @@ func (c *Conn) Prepare(query string) (driver.Stmt, error) {
 
 func (c *Conn) Close() error {
 	// TODO: close prepared statements
-	c.mapi.Disconnect()
-	c.mapi = nil
+	if c.mapi != nil {
+		c.mapi.Disconnect()
+		c.mapi = nil
+	}
 	return nil
 }
 
-func (c *Conn) begin(readonly bool, isolation driver.IsolationLevel) (driver.Tx, error) {
-	t := newTx(c)
+func (c *Conn) begin(ctx context.Context, readonly bool, isolation driver.IsolationLevel) (driver.Tx, error) {
+	t := newTx(ctx, c)
+	if !c.session.defaultAutoCommit {
+		// Without autocommit the session is always in a transaction, the
+		// transaction ends with the next COMMIT or ROLLBACK
+		if readonly || isolation != driver.IsolationLevel(sql.LevelDefault) {
+			t.err = fmt.Errorf("monetdb: transaction options are not supported when autocommit is disabled")
+			return t, t.err
+		}
+		c.tx = t
+		return t, nil
+	}
+	if c.session.inTransaction {
+		// For example when START TRANSACTION was executed as a query
+		t.err = fmt.Errorf("monetdb: connection is already in a transaction")
+		return t, t.err
+	}
+
 	var query string
 	if readonly {
 		// The monetdb documentation mentions this options, but it is not supported
@@ -99,10 +195,12 @@ func (c *Conn) begin(readonly bool, isolation driver.IsolationLevel) (driver.Tx,
 		}
 	}
 
-	err := executeStmt(c, query)
+	err := executeStmtContext(ctx, c, query)
 
 	if err != nil {
 		t.err = err
+	} else {
+		c.tx = t
 	}
 
 	return t, t.err
@@ -110,11 +208,13 @@ func (c *Conn) begin(readonly bool, isolation driver.IsolationLevel) (driver.Tx,
 
 // Deprecated: Use BeginTx instead
 func (c *Conn) Begin() (driver.Tx, error) {
-	return c.begin(false, driver.IsolationLevel(sql.LevelDefault))
+	return c.begin(context.Background(), false, driver.IsolationLevel(sql.LevelDefault))
 }
 
+// BeginTx starts a transaction. The context is used to start the
+// transaction, and to commit or roll it back.
 func (c *Conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
-	tx, err := c.begin(opts.ReadOnly, opts.Isolation)
+	tx, err := c.begin(ctx, opts.ReadOnly, opts.Isolation)
 	return tx, err
 }
 
@@ -145,3 +245,48 @@ func (c *Conn) CheckNamedValue(arg *driver.NamedValue) error {
 	_, err := mapi.ConvertToMonet(arg.Value)
 	return err
 }
+
+// activeTx returns the transaction that was started with BeginTx.
+func (c *Conn) activeTx() (*Tx, error) {
+	if c.tx == nil {
+		return nil, fmt.Errorf("monetdb: no active transaction")
+	}
+	return c.tx, nil
+}
+
+// Savepoint creates a savepoint in the active transaction of the
+// connection. The sql package does not provide access to the driver
+// transaction, use sql.Conn.Raw to call this function:
+//
+//	tx, err := conn.BeginTx(ctx, nil)
+//	...
+//	err = conn.Raw(func(driverConn any) error {
+//		return driverConn.(*monetdb.Conn).Savepoint("batch1")
+//	})
+func (c *Conn) Savepoint(name string) error {
+	tx, err := c.activeTx()
+	if err != nil {
+		return err
+	}
+	return tx.Savepoint(name)
+}
+
+// RollbackTo rolls the active transaction of the connection back to the
+// savepoint. See Savepoint for how to call this function.
+func (c *Conn) RollbackTo(name string) error {
+	tx, err := c.activeTx()
+	if err != nil {
+		return err
+	}
+	return tx.RollbackTo(name)
+}
+
+// Release removes the savepoint from the active transaction of the
+// connection. See Savepoint for how to call this function.
+func (c *Conn) Release(name string) error {
+	tx, err := c.activeTx()
+	if err != nil {
+		return err
+	}
+	return tx.Release(name)
+}
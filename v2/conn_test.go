@@ -0,0 +1,71 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package monetdb
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/MonetDB/MonetDB-Go/v2/mapi"
+)
+
+func TestConnCheckErr(t *testing.T) {
+	t.Run("Verify ErrBadConn when the command was not sent", func(t *testing.T) {
+		c := &Conn{mapi: &mapi.MapiConn{}}
+		err := c.checkErr(&mapi.ConnError{Err: io.EOF, Sent: false})
+		if err != driver.ErrBadConn {
+			t.Errorf("Unexpected error %v", err)
+		}
+		if c.IsValid() {
+			t.Error("Connection is still valid after failure")
+		}
+	})
+
+	t.Run("Verify original error when the command was sent", func(t *testing.T) {
+		c := &Conn{mapi: &mapi.MapiConn{}}
+		err := c.checkErr(&mapi.ConnError{Err: io.EOF, Sent: true})
+		if !errors.Is(err, io.EOF) {
+			t.Errorf("Unexpected error %v", err)
+		}
+		if c.IsValid() {
+			t.Error("Connection is still valid after failure")
+		}
+	})
+
+	t.Run("Verify server errors do not invalidate the connection", func(t *testing.T) {
+		c := &Conn{mapi: &mapi.MapiConn{}}
+		resp, _ := mapi.ParseResponse("!42S02!SELECT: no such table 'x'")
+		c.checkErr(resp.Err())
+		if !c.IsValid() {
+			t.Error("Connection is not valid after server error")
+		}
+	})
+
+	t.Run("Verify closed results are kept when they can not be released", func(t *testing.T) {
+		c := &Conn{mapi: &mapi.MapiConn{}}
+		c.closeResult(3)
+		c.closePrepared(5)
+		if len(c.session.closedResults) != 1 || c.session.closedResults[0] != 3 {
+			t.Errorf("Unexpected closed results %v", c.session.closedResults)
+		}
+		if len(c.session.closedPrepared) != 1 || c.session.closedPrepared[0] != 5 {
+			t.Errorf("Unexpected closed prepared statements %v", c.session.closedPrepared)
+		}
+	})
+
+	t.Run("Verify ErrBadConn on a bad connection", func(t *testing.T) {
+		c := &Conn{mapi: &mapi.MapiConn{}, bad: true}
+		if err := c.Ping(context.Background()); err != driver.ErrBadConn {
+			t.Errorf("Unexpected error from ping %v", err)
+		}
+		_, err := c.ExecContext(context.Background(), "select 1", nil)
+		if err != driver.ErrBadConn {
+			t.Errorf("Unexpected error from exec %v", err)
+		}
+	})
+}
@@ -0,0 +1,83 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package monetdb
+
+import (
+	"context"
+	"database/sql/driver"
+	"time"
+
+	"github.com/MonetDB/MonetDB-Go/v2/mapi"
+)
+
+// Connector creates connections with the same DSN and settings. Use it with
+// sql.OpenDB to configure options that can not be set in the DSN.
+//
+//	connector, err := monetdb.NewConnector("monetdb:monetdb@localhost:50000/monetdb")
+//	connector.StatementTimeout = 30 * time.Second
+//	connector.ContextTimeout = true
+//	db := sql.OpenDB(connector)
+type Connector struct {
+	dsn    string
+	driver *Driver
+
+	// StatementTimeout is the default query timeout of the sessions that
+	// are created by the connector. The server aborts a statement that
+	// runs longer. The timeout is rounded up to whole seconds. When it is
+	// zero, the server default is used.
+	StatementTimeout time.Duration
+
+	// ContextTimeout enables server-side timeouts for contexts with a
+	// deadline. When a statement is executed with such a context, the
+	// query timeout of the session is set to the remaining time, if that is
+	// shorter than the StatementTimeout. The server then stops working on
+	// the statement when the deadline is exceeded, instead of only the
+	// client. The timeout is restored for the next statement.
+	ContextTimeout bool
+}
+
+// NewConnector returns a Connector for the DSN. The DSN is validated, but
+// no connection is made.
+func NewConnector(dsn string) (*Connector, error) {
+	if _, err := mapi.NewMapi(dsn); err != nil {
+		return nil, err
+	}
+	return &Connector{dsn: dsn, driver: &Driver{}}, nil
+}
+
+// OpenConnector implements driver.DriverContext.
+func (d *Driver) OpenConnector(name string) (driver.Connector, error) {
+	c, err := NewConnector(name)
+	if err != nil {
+		return nil, err
+	}
+	c.driver = d
+	return c, nil
+}
+
+// Connect implements driver.Connector.
+func (c *Connector) Connect(ctx context.Context) (driver.Conn, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	conn, err := newConn(c.dsn)
+	if err != nil {
+		return nil, err
+	}
+	conn.session.contextTimeout = c.ContextTimeout
+	if c.StatementTimeout > 0 {
+		conn.session.baseTimeout = timeoutSeconds(c.StatementTimeout)
+		if err := conn.session.setQueryTimeout(conn.mapi, conn.session.baseTimeout); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// Driver implements driver.Connector.
+func (c *Connector) Driver() driver.Driver {
+	return c.driver
+}
@@ -0,0 +1,162 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package monetdb
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestConnectorTimeoutIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	connector, err := NewConnector("monetdb:monetdb@localhost:50000/monetdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	connector.StatementTimeout = 2 * time.Second
+	connector.ContextTimeout = true
+	db := sql.OpenDB(connector)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
+
+	t.Run("Exec create procedure", func(t *testing.T) {
+		_, err := db.Exec("create procedure timeout_sleep(i int) external name alarm.sleep")
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Statement timeout aborts a long statement", func(t *testing.T) {
+		_, err := db.Exec("call timeout_sleep(4000)")
+		if !IsQueryTimeout(err) {
+			t.Errorf("Unexpected error %v", err)
+		}
+	})
+
+	t.Run("Statement within the timeout succeeds", func(t *testing.T) {
+		if _, err := db.Exec("call timeout_sleep(10)"); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("Context deadline is used as the timeout", func(t *testing.T) {
+		conn, err := db.Conn(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+		defer cancel()
+		conn.ExecContext(ctx, "call timeout_sleep(4000)")
+
+		queryTimeout := func() int {
+			var timeout int
+			err := conn.Raw(func(driverConn any) error {
+				timeout = driverConn.(*Conn).session.queryTimeout
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			return timeout
+		}
+		if timeout := queryTimeout(); timeout != 1 {
+			t.Errorf("Unexpected query timeout %d, expected: 1", timeout)
+		}
+
+		if _, err := conn.ExecContext(context.Background(), "call timeout_sleep(10)"); err != nil {
+			t.Fatal(err)
+		}
+		if timeout := queryTimeout(); timeout != 2 {
+			t.Errorf("Unexpected query timeout %d, expected: 2", timeout)
+		}
+	})
+
+	t.Run("Commit uses the statement timeout", func(t *testing.T) {
+		conn, err := db.Conn(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		tx, err := conn.BeginTx(context.Background(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+		defer cancel()
+		if _, err := tx.ExecContext(ctx, "call timeout_sleep(10)"); err != nil {
+			t.Fatal(err)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatal(err)
+		}
+		var timeout int
+		conn.Raw(func(driverConn any) error {
+			timeout = driverConn.(*Conn).session.queryTimeout
+			return nil
+		})
+		if timeout != 2 {
+			t.Errorf("Unexpected query timeout %d, expected: 2", timeout)
+		}
+	})
+
+	t.Run("Rollback ends a transaction that was aborted by a timeout", func(t *testing.T) {
+		conn, err := db.Conn(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		tx, err := conn.BeginTx(context.Background(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The server aborts the statement and the transaction after the
+		// timeout of one second, which is shorter than the statement timeout
+		ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+		defer cancel()
+		if _, err := tx.ExecContext(ctx, "call timeout_sleep(4000)"); err == nil {
+			t.Fatal("Statement did not time out")
+		}
+		if err := tx.Rollback(); err != nil {
+			t.Fatal(err)
+		}
+
+		// The server is no longer in the transaction
+		tx, err = conn.BeginTx(context.Background(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tx.Exec("call timeout_sleep(10)"); err != nil {
+			t.Fatal(err)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatal(err)
+		}
+		var timeout int
+		conn.Raw(func(driverConn any) error {
+			timeout = driverConn.(*Conn).session.queryTimeout
+			return nil
+		})
+		if timeout != 2 {
+			t.Errorf("Unexpected query timeout %d, expected: 2", timeout)
+		}
+	})
+
+	t.Run("Exec drop procedure", func(t *testing.T) {
+		_, err := db.Exec("drop procedure timeout_sleep")
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"database/sql"
 	"context"
 	"testing"
+	"time"
 )
   
 func TestContextDBIntegration(t *testing.T) {
@@ -623,3 +624,58 @@ func TestContextRowIntegration(t *testing.T) {
 		}
 	})
 }
+
+func TestContextRowsFetchIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	db, err := sql.Open("monetdb", "monetdb:monetdb@localhost:50000/monetdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	t.Run("Cancelled context stops fetching rows", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		rows, err := db.QueryContext(ctx, "select * from sys.generate_series(0, 1000)")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+		count := 0
+		for rows.Next() {
+			count++
+			if count == 10 {
+				cancel()
+			}
+		}
+		if count >= 1000 {
+			t.Errorf("Unexpected number of rows %d", count)
+		}
+		if err := rows.Err(); err != context.Canceled {
+			t.Errorf("Unexpected error %v", err)
+		}
+	})
+
+	t.Run("Rows are fetched within the deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		rows, err := db.QueryContext(ctx, "select * from sys.generate_series(0, 1000)")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+		count := 0
+		for rows.Next() {
+			count++
+		}
+		if err := rows.Err(); err != nil {
+			t.Error(err)
+		}
+		if count != 1000 {
+			t.Errorf("Unexpected number of rows %d", count)
+		}
+	})
+}
@@ -0,0 +1,118 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package monetdb
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+)
+
+// QueryCursor executes the query and returns the result as a cursor. The
+// result set stays on the server, so the rows can be read in any order
+// without running the query again. Use sql.Conn.Raw to call this function:
+//
+//	err = conn.Raw(func(driverConn any) error {
+//		rows, err := driverConn.(*monetdb.Conn).QueryCursor(ctx, "select * from t")
+//		if err != nil {
+//			return err
+//		}
+//		defer rows.Close()
+//		page, err := rows.Fetch(100, 20)
+//		...
+//	})
+//
+// The returned Rows use the connection, so they must not be used after
+// the function that is passed to Raw returns.
+func (c *Conn) QueryCursor(ctx context.Context, query string, args ...any) (*Rows, error) {
+	namedArgs := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		namedArgs[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+		if err := c.CheckNamedValue(&namedArgs[i]); err != nil {
+			return nil, err
+		}
+	}
+	if len(namedArgs) == 0 {
+		namedArgs = nil
+	}
+
+	rows, err := c.QueryContext(ctx, query, namedArgs)
+	if err != nil {
+		return nil, err
+	}
+	r := rows.(*Rows)
+	if len(r.schema) == 0 {
+		r.Close()
+		return nil, fmt.Errorf("monetdb: query didn't result in a resultset")
+	}
+	return r, nil
+}
+
+// RowCount returns the total number of rows of the result set. It is known
+// as soon as the query is executed, before the rows are fetched.
+func (r *Rows) RowCount() int {
+	return r.rowCount
+}
+
+// Position returns the offset of the row that is returned by the next
+// call to Next.
+func (r *Rows) Position() int {
+	return r.rowNum
+}
+
+// Seek moves the cursor to the row at the offset, so it is returned by the
+// next call to Next. An offset equal to the number of rows moves the cursor
+// past the last row. The rows are fetched when they are read.
+func (r *Rows) Seek(offset int) error {
+	if !r.active {
+		return fmt.Errorf("monetdb: rows closed")
+	}
+	if offset < 0 || offset > r.rowCount {
+		return fmt.Errorf("monetdb: offset %d out of range [0, %d]", offset, r.rowCount)
+	}
+	r.rowNum = offset
+	return nil
+}
+
+// Rewind moves the cursor to the first row.
+func (r *Rows) Rewind() error {
+	return r.Seek(0)
+}
+
+// Fetch returns at most n rows, starting at the offset, and moves the cursor
+// to the row after them. The window is fetched from the server with a
+// single request, unless it was already received. The values have the Go
+// type of the column, so text is returned as a string.
+func (r *Rows) Fetch(offset int, n int) ([][]driver.Value, error) {
+	if err := r.Seek(offset); err != nil {
+		return nil, err
+	}
+	if n < 0 {
+		return nil, fmt.Errorf("monetdb: invalid number of rows %d", n)
+	}
+	if err := r.ctx.Err(); err != nil {
+		return nil, err
+	}
+	end := min(r.rowCount, offset+n)
+	if end == offset {
+		return [][]driver.Value{}, nil
+	}
+
+	if !r.buffered(offset) || !r.buffered(end-1) {
+		if err := r.fetchBlock(offset, end-offset); err != nil {
+			return nil, err
+		}
+	}
+	res := make([][]driver.Value, 0, end-offset)
+	for _, tuple := range r.tuples[offset-r.offset : end-r.offset] {
+		row, err := r.convertRow(tuple)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, row)
+	}
+	r.rowNum = end
+	return res, nil
+}
@@ -0,0 +1,129 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package monetdb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestCursorIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	db, err := sql.Open("monetdb", "monetdb:monetdb@localhost:50000/monetdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	const n = 1000
+
+	t.Run("Page through a result set", func(t *testing.T) {
+		err := conn.Raw(func(driverConn any) error {
+			rows, err := driverConn.(*Conn).QueryCursor(ctx, fmt.Sprintf("select cast(value as bigint) from sys.generate_series(0, %d)", n))
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+
+			if rows.RowCount() != n {
+				t.Errorf("Unexpected row count %d, expected: %d", rows.RowCount(), n)
+			}
+			for _, offset := range []int{500, 20, 980, 0} {
+				page, err := rows.Fetch(offset, 50)
+				if err != nil {
+					return err
+				}
+				if len(page) != min(50, n-offset) {
+					t.Errorf("Unexpected page size %d at offset %d", len(page), offset)
+				}
+				for i, row := range page {
+					if row[0] != int64(offset+i) {
+						t.Errorf("Unexpected value %v at row %d", row[0], offset+i)
+					}
+				}
+			}
+
+			if err := rows.Seek(n - 2); err != nil {
+				return err
+			}
+			dest := make([]driver.Value, 1)
+			count := 0
+			for rows.Next(dest) == nil {
+				count++
+			}
+			if count != 2 {
+				t.Errorf("Unexpected number of rows %d after seek, expected: 2", count)
+			}
+
+			if err := rows.Rewind(); err != nil {
+				return err
+			}
+			if err := rows.Next(dest); err != nil {
+				return err
+			}
+			if dest[0] != int64(0) {
+				t.Errorf("Unexpected value %v after rewind", dest[0])
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Fetch columns", func(t *testing.T) {
+		err := conn.Raw(func(driverConn any) error {
+			query := "select value, cast(value as double) / 2, 'v' || value, case when value %% 2 = 0 then null else value end from sys.generate_series(0, %d)"
+			rows, err := driverConn.(*Conn).QueryCursor(ctx, fmt.Sprintf(query, n))
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+
+			total := 0
+			for {
+				block, err := rows.FetchColumns(300)
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					return err
+				}
+				ids, halves, names, odd := block.Columns[0], block.Columns[1], block.Columns[2], block.Columns[3]
+				for i := 0; i < block.Len; i++ {
+					v := int64(block.Offset + i)
+					if ids.Int64[i] != v || halves.Float64[i] != float64(v)/2 || names.String[i] != fmt.Sprintf("v%d", v) {
+						t.Fatalf("Unexpected values at row %d", v)
+					}
+					if odd.Nulls.IsNull(i) != (v%2 == 0) {
+						t.Fatalf("Unexpected null at row %d", v)
+					}
+				}
+				total += block.Len
+			}
+			if total != n {
+				t.Errorf("Unexpected number of rows %d, expected: %d", total, n)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+}
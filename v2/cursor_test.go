@@ -0,0 +1,126 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package monetdb
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/MonetDB/MonetDB-Go/v2/mapi"
+)
+
+// newBufferedRows returns rows of which all values were received with the
+// first block, so no connection is needed to read them.
+func newBufferedRows(n int) *Rows {
+	r := newRows(context.Background(), nil)
+	r.rowCount = n
+	r.tuples = make([]string, n)
+	for i := range r.tuples {
+		r.tuples[i] = fmt.Sprintf("[ %d\t]", i)
+	}
+	r.schema = []mapi.TableElement{{ColumnName: "value", ColumnType: mapi.MDB_BIGINT}}
+	return r
+}
+
+func TestRowsCursor(t *testing.T) {
+	t.Run("Verify Seek and Rewind", func(t *testing.T) {
+		r := newBufferedRows(5)
+		dest := make([]driver.Value, 1)
+		if err := r.Seek(3); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.Next(dest); err != nil || dest[0] != int64(3) {
+			t.Errorf("Unexpected value %v, expected: 3", dest[0])
+		}
+		if err := r.Rewind(); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.Next(dest); err != nil || dest[0] != int64(0) {
+			t.Errorf("Unexpected value %v, expected: 0", dest[0])
+		}
+		if err := r.Seek(5); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.Next(dest); err != io.EOF {
+			t.Errorf("Unexpected error %v", err)
+		}
+		if err := r.Seek(6); err == nil {
+			t.Error("Seek beyond the end of the result did not fail")
+		}
+		if err := r.Seek(-1); err == nil {
+			t.Error("Seek before the start of the result did not fail")
+		}
+	})
+
+	t.Run("Verify Fetch", func(t *testing.T) {
+		r := newBufferedRows(5)
+		rows, err := r.Fetch(1, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rows) != 2 || rows[0][0] != int64(1) || rows[1][0] != int64(2) {
+			t.Errorf("Unexpected rows %v", rows)
+		}
+		if r.Position() != 3 {
+			t.Errorf("Invalid position: %d, expected: 3", r.Position())
+		}
+		rows, err = r.Fetch(4, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rows) != 1 || rows[0][0] != int64(4) {
+			t.Errorf("Unexpected rows %v", rows)
+		}
+		rows, err = r.Fetch(5, 10)
+		if err != nil || len(rows) != 0 {
+			t.Errorf("Unexpected rows %v, error %v", rows, err)
+		}
+	})
+
+	t.Run("Verify closed rows", func(t *testing.T) {
+		r := newBufferedRows(5)
+		r.Close()
+		if _, err := r.Fetch(0, 1); err == nil {
+			t.Error("Fetch on closed rows did not fail")
+		}
+	})
+}
+
+func TestRowsFetchColumns(t *testing.T) {
+	r := newBufferedRows(5)
+	if err := r.Seek(1); err != nil {
+		t.Fatal(err)
+	}
+	block, err := r.FetchColumns(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if block.Offset != 1 || block.Len != 3 || len(block.Columns) != 1 {
+		t.Fatalf("Unexpected block %+v", block)
+	}
+	col := block.Columns[0]
+	if col.Name != "value" || col.Kind != KindInt64 {
+		t.Errorf("Unexpected column %s of kind %v", col.Name, col.Kind)
+	}
+	for i, v := range col.Int64 {
+		if v != int64(i+1) {
+			t.Errorf("Invalid value: %d, expected: %d", v, i+1)
+		}
+	}
+
+	block, err = r.FetchColumns(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if block.Len != 1 || block.Columns[0].Int64[0] != 4 {
+		t.Errorf("Unexpected block %+v", block)
+	}
+	if _, err := r.FetchColumns(10); err != io.EOF {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
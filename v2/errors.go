@@ -0,0 +1,94 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package monetdb
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/MonetDB/MonetDB-Go/v2/mapi"
+)
+
+// Error is the error that is returned when the MonetDB server reports an
+// error. Use errors.As to retrieve the SQLSTATE code and the message.
+//
+//	var e *monetdb.Error
+//	if errors.As(err, &e) {
+//		fmt.Println(e.SQLState, e.Message)
+//	}
+type Error = mapi.Error
+
+// sqlState returns the SQLSTATE code and the message of the server error
+// that is wrapped in err, or empty strings when err is not a server error.
+func sqlState(err error) (string, string) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.SQLState, e.Message
+	}
+	return "", ""
+}
+
+// IsSyntaxError reports whether err is a syntax error in the query. MonetDB
+// uses SQLSTATE 42000 for several kinds of errors, so the message is also
+// checked.
+func IsSyntaxError(err error) bool {
+	state, msg := sqlState(err)
+	return state == "42000" && strings.Contains(strings.ToLower(msg), "syntax error")
+}
+
+// IsUndefinedObject reports whether err is caused by a reference to a table,
+// column or schema that does not exist.
+func IsUndefinedObject(err error) bool {
+	state, _ := sqlState(err)
+	switch state {
+	case "42S02", "42S22", "3F000":
+		return true
+	}
+	return false
+}
+
+// IsConstraintViolation reports whether err is caused by the violation of a
+// primary key, unique, foreign key, not null or check constraint. MonetDB
+// reports most constraint violations with SQLSTATE 40002.
+func IsConstraintViolation(err error) bool {
+	state, _ := sqlState(err)
+	return state == "40002" || strings.HasPrefix(state, "23")
+}
+
+// IsConcurrencyConflict reports whether err is caused by a conflict with a
+// concurrent transaction. MonetDB uses optimistic concurrency control, so
+// the transaction is aborted and can be retried. Depending on the version,
+// the server reports a write-write conflict when the statement is executed
+// or when the transaction is committed.
+func IsConcurrencyConflict(err error) bool {
+	var e *Error
+	if !errors.As(err, &e) {
+		return false
+	}
+	if e.SQLState == "40001" {
+		return true
+	}
+	msg := strings.ToLower(e.Message)
+	return strings.Contains(msg, "concurrency conflict") || strings.Contains(msg, "transaction conflict")
+}
+
+// IsPermissionDenied reports whether err is caused by insufficient
+// privileges of the current user or role.
+func IsPermissionDenied(err error) bool {
+	state, msg := sqlState(err)
+	if strings.HasPrefix(state, "42") {
+		msg = strings.ToLower(msg)
+		return strings.Contains(msg, "insufficient privileges") || strings.Contains(msg, "access denied")
+	}
+	return false
+}
+
+// IsQueryTimeout reports whether err is caused by a statement that the
+// server aborted, because it ran longer than the query timeout of the
+// session. See Connector.StatementTimeout.
+func IsQueryTimeout(err error) bool {
+	state, msg := sqlState(err)
+	return state == "HY008" || strings.Contains(strings.ToLower(msg), "aborted due to timeout")
+}
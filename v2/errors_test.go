@@ -0,0 +1,72 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package monetdb
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/MonetDB/MonetDB-Go/v2/mapi"
+)
+
+func serverError(t *testing.T, response string) error {
+	resp, err := mapi.ParseResponse(response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.Err()
+}
+
+func TestErrorPredicates(t *testing.T) {
+	type tc struct {
+		response string
+		pred     func(error) bool
+		e        bool
+	}
+	var tcs = []tc{
+		{"!42000!syntax error, unexpected IDENT in: \"selec\"", IsSyntaxError, true},
+		{"!42000!SELECT: identifier 'x' unknown", IsSyntaxError, false},
+		{"!42S02!SELECT: no such table 'x'", IsUndefinedObject, true},
+		{"!42S22!SELECT: no such column 'y'", IsUndefinedObject, true},
+		{"!3F000!SET SCHEMA: no such schema 's'", IsUndefinedObject, true},
+		{"!40002!INSERT INTO: PRIMARY KEY constraint 't.t_id_pkey' violated", IsConstraintViolation, true},
+		{"!42S02!SELECT: no such table 'x'", IsConstraintViolation, false},
+		{"!40000!COMMIT: transaction is aborted because of concurrency conflicts, will ROLLBACK instead", IsConcurrencyConflict, true},
+		{"!42000!UPDATE: transaction conflict detected", IsConcurrencyConflict, true},
+		{"!40000!COMMIT: failed", IsConcurrencyConflict, false},
+		{"!42000!SELECT: access denied for monetdb to table 'sys.x'", IsPermissionDenied, true},
+		{"!HY008!Query aborted due to timeout", IsQueryTimeout, true},
+		{"!42000!SELECT: no such table 'x'", IsQueryTimeout, false},
+	}
+
+	for _, c := range tcs {
+		err := serverError(t, c.response)
+		if c.pred(err) != c.e {
+			t.Errorf("Unexpected result for %s, expected: %v", c.response, c.e)
+		}
+		if c.pred(fmt.Errorf("wrapped: %w", err)) != c.e {
+			t.Errorf("Unexpected result for wrapped %s, expected: %v", c.response, c.e)
+		}
+	}
+
+	t.Run("Verify predicates with other errors", func(t *testing.T) {
+		err := errors.New("42S02!SELECT: no such table 'x'")
+		if IsUndefinedObject(err) || IsSyntaxError(nil) {
+			t.Error("Predicate matched an error that is not a server error")
+		}
+	})
+
+	t.Run("Verify errors.As with monetdb.Error", func(t *testing.T) {
+		err := fmt.Errorf("wrapped: %w", serverError(t, "!42S02!SELECT: no such table 'x'\n!second line"))
+		var e *Error
+		if !errors.As(err, &e) {
+			t.Fatal("errors.As did not find monetdb.Error")
+		}
+		if e.SQLState != "42S02" || len(e.Lines) != 2 {
+			t.Errorf("Unexpected error %+v", e)
+		}
+	})
+}
@@ -0,0 +1,216 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package mapi
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ColumnKind is the Go type of the values of a Column.
+type ColumnKind int
+
+const (
+	KindString ColumnKind = iota
+	KindInt64
+	KindFloat64
+	KindBool
+	KindTime
+	KindBytes
+	KindBigInt
+	KindDecimal
+)
+
+func (k ColumnKind) String() string {
+	switch k {
+	case KindString:
+		return "string"
+	case KindInt64:
+		return "int64"
+	case KindFloat64:
+		return "float64"
+	case KindBool:
+		return "bool"
+	case KindTime:
+		return "time.Time"
+	case KindBytes:
+		return "[]byte"
+	case KindBigInt:
+		return "*big.Int"
+	case KindDecimal:
+		return "Decimal"
+	default:
+		return fmt.Sprintf("ColumnKind(%d)", int(k))
+	}
+}
+
+// columnKinds maps the types of the server to the kind of the column. The
+// types that are not listed are returned as text.
+var columnKinds = map[string]ColumnKind{
+	MDB_TINYINT:     KindInt64,
+	MDB_SMALLINT:    KindInt64,
+	MDB_SHORTINT:    KindInt64,
+	MDB_INT:         KindInt64,
+	MDB_MEDIUMINT:   KindInt64,
+	MDB_WRD:         KindInt64,
+	MDB_BIGINT:      KindInt64,
+	MDB_LONGINT:     KindInt64,
+	MDB_SERIAL:      KindInt64,
+	MDB_HUGEINT:     KindBigInt,
+	MDB_REAL:        KindFloat64,
+	MDB_FLOAT:       KindFloat64,
+	MDB_DOUBLE:      KindFloat64,
+	MDB_DECIMAL:     KindDecimal,
+	MDB_BOOLEAN:     KindBool,
+	MDB_DATE:        KindTime,
+	MDB_TIME:        KindTime,
+	MDB_TIMESTAMP:   KindTime,
+	MDB_TIMESTAMPTZ: KindTime,
+	MDB_TIMETZ:      KindTime,
+	MDB_BLOB:        KindBytes,
+}
+
+// NullBitmap marks the rows of a column that are NULL. Bit i is set when
+// row i is NULL.
+type NullBitmap []uint64
+
+func newNullBitmap(n int) NullBitmap {
+	return make(NullBitmap, (n+63)/64)
+}
+
+// IsNull reports whether row i is NULL.
+func (b NullBitmap) IsNull(i int) bool {
+	return b[i/64]&(1<<(uint(i)%64)) != 0
+}
+
+func (b NullBitmap) set(i int) {
+	b[i/64] |= 1 << (uint(i) % 64)
+}
+
+// Column contains the values of one column of a block of rows. Only the
+// slice that matches the Kind is filled, it has one element for every row.
+// The element of a NULL value is the zero value.
+type Column struct {
+	Name string
+	// Type is the type of the column in the database
+	Type string
+	Kind ColumnKind
+
+	Int64   []int64
+	Float64 []float64
+	String  []string
+	Bool    []bool
+	Time    []time.Time
+	Bytes   [][]byte
+	BigInt  []*big.Int
+	Decimal []Decimal
+
+	Nulls NullBitmap
+}
+
+// ColumnBlock is a block of rows of a result set, stored per column.
+type ColumnBlock struct {
+	// Offset is the position of the first row in the result set
+	Offset  int
+	Len     int
+	Columns []Column
+}
+
+// DecodeColumns converts the unconverted rows of a response into columns.
+// The values are converted directly into the slice of their column.
+func DecodeColumns(schema []TableElement, tuples []string) ([]Column, error) {
+	n := len(tuples)
+	cols := make([]Column, len(schema))
+	for i, e := range schema {
+		c := Column{
+			Name:  e.ColumnName,
+			Type:  e.ColumnType,
+			Kind:  columnKinds[e.ColumnType],
+			Nulls: newNullBitmap(n),
+		}
+		switch c.Kind {
+		case KindInt64:
+			c.Int64 = make([]int64, n)
+		case KindFloat64:
+			c.Float64 = make([]float64, n)
+		case KindBool:
+			c.Bool = make([]bool, n)
+		case KindTime:
+			c.Time = make([]time.Time, n)
+		case KindBytes:
+			c.Bytes = make([][]byte, n)
+		case KindBigInt:
+			c.BigInt = make([]*big.Int, n)
+		case KindDecimal:
+			c.Decimal = make([]Decimal, n)
+		default:
+			c.String = make([]string, n)
+		}
+		cols[i] = c
+	}
+
+	for row, tuple := range tuples {
+		fields := splitTuple(tuple)
+		if len(fields) != len(cols) {
+			return nil, fmt.Errorf("mapi: length of row doesn't match header")
+		}
+		for i, field := range fields {
+			if err := cols[i].decode(row, strings.TrimSpace(field)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return cols, nil
+}
+
+func (c *Column) decode(row int, v string) error {
+	if v == "NULL" {
+		c.Nulls.set(row)
+		return nil
+	}
+
+	var err error
+	switch c.Kind {
+	case KindInt64:
+		c.Int64[row], err = strconv.ParseInt(v, 10, 64)
+	case KindFloat64:
+		c.Float64[row], err = strconv.ParseFloat(v, 64)
+	case KindBool:
+		c.Bool[row], err = strconv.ParseBool(v)
+	case KindTime:
+		c.Time[row], err = parseTime(v)
+	case KindBytes:
+		var b Value
+		b, err = toByteArray(v)
+		if err == nil {
+			c.Bytes[row] = b.([]byte)
+		}
+	case KindBigInt:
+		var i Value
+		i, err = toHugeInt(v)
+		if err == nil {
+			c.BigInt[row] = i.(*big.Int)
+		}
+	case KindDecimal:
+		c.Decimal[row], err = ParseDecimal(v)
+	default:
+		if strings.HasPrefix(v, "\"") {
+			var s Value
+			s, err = strip(v)
+			if err == nil {
+				c.String[row] = s.(string)
+			}
+		} else {
+			c.String[row] = v
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("mapi: invalid %s value %q: %v", c.Type, v, err)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package mapi
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDecodeColumns(t *testing.T) {
+	schema := []TableElement{
+		{ColumnName: "id", ColumnType: MDB_INT},
+		{ColumnName: "price", ColumnType: MDB_DOUBLE},
+		{ColumnName: "name", ColumnType: MDB_VARCHAR},
+		{ColumnName: "ok", ColumnType: MDB_BOOLEAN},
+		{ColumnName: "ts", ColumnType: MDB_TIMESTAMP},
+	}
+	tuples := []string{
+		"[ 1,\t1.5,\t\"first\",\ttrue,\t2022-03-04 05:06:07\t]",
+		"[ NULL,\tNULL,\tNULL,\tNULL,\tNULL\t]",
+		"[ 3,\t-2,\t\"NULL\",\tfalse,\t2022-03-04 05:06:07\t]",
+	}
+
+	cols, err := DecodeColumns(schema, tuples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cols) != 5 {
+		t.Fatalf("Unexpected number of columns %d", len(cols))
+	}
+
+	type tc struct {
+		kind ColumnKind
+		e    ColumnKind
+	}
+	var tcs = []tc{
+		{cols[0].Kind, KindInt64},
+		{cols[1].Kind, KindFloat64},
+		{cols[2].Kind, KindString},
+		{cols[3].Kind, KindBool},
+		{cols[4].Kind, KindTime},
+	}
+	for _, c := range tcs {
+		if c.kind != c.e {
+			t.Errorf("Invalid kind: %v, expected: %v", c.kind, c.e)
+		}
+	}
+
+	if cols[0].Int64[0] != 1 || cols[0].Int64[2] != 3 {
+		t.Errorf("Unexpected values %v", cols[0].Int64)
+	}
+	if cols[1].Float64[0] != 1.5 || cols[1].Float64[2] != -2 {
+		t.Errorf("Unexpected values %v", cols[1].Float64)
+	}
+	if cols[2].String[0] != "first" || cols[2].String[2] != "NULL" {
+		t.Errorf("Unexpected values %v", cols[2].String)
+	}
+	if !cols[3].Bool[0] || cols[3].Bool[2] {
+		t.Errorf("Unexpected values %v", cols[3].Bool)
+	}
+	if !cols[4].Time[0].Equal(time.Date(2022, 3, 4, 5, 6, 7, 0, time.UTC)) {
+		t.Errorf("Unexpected values %v", cols[4].Time)
+	}
+
+	for i, c := range cols {
+		if c.Nulls.IsNull(0) || !c.Nulls.IsNull(1) || c.Nulls.IsNull(2) {
+			t.Errorf("Unexpected nulls in column %d", i)
+		}
+	}
+}
+
+func TestDecodeColumnsDecimal(t *testing.T) {
+	schema := []TableElement{{ColumnName: "amount", ColumnType: MDB_DECIMAL}}
+	tuples := []string{"[ 12345678901234.56789	]", "[ NULL	]", "[ -0.10	]"}
+
+	cols, err := DecodeColumns(schema, tuples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := cols[0]
+	if c.Kind != KindDecimal {
+		t.Fatalf("Invalid kind: %v, expected: %v", c.Kind, KindDecimal)
+	}
+	// The value does not fit in a float64 without loss
+	if s := c.Decimal[0].String(); s != "12345678901234.56789" {
+		t.Errorf("Unexpected value %s", s)
+	}
+	if s := c.Decimal[2].String(); s != "-0.10" {
+		t.Errorf("Unexpected value %s", s)
+	}
+	if !c.Nulls.IsNull(1) {
+		t.Error("NULL value is not marked")
+	}
+}
+
+func TestDecodeColumnsErrors(t *testing.T) {
+	schema := []TableElement{{ColumnName: "id", ColumnType: MDB_INT}}
+
+	if _, err := DecodeColumns(schema, []string{"[ abc\t]"}); err == nil {
+		t.Error("Invalid integer did not fail")
+	}
+	if _, err := DecodeColumns(schema, []string{"[ 1,\t2\t]"}); err == nil {
+		t.Error("Row with too many fields did not fail")
+	}
+}
+
+func TestNullBitmap(t *testing.T) {
+	b := newNullBitmap(130)
+	if len(b) != 3 {
+		t.Fatalf("Invalid length: %d, expected: 3", len(b))
+	}
+	b.set(0)
+	b.set(64)
+	b.set(129)
+	for i := 0; i < 130; i++ {
+		e := i == 0 || i == 64 || i == 129
+		if b.IsNull(i) != e {
+			t.Errorf("Invalid value for row %d, expected: %v", i, e)
+		}
+	}
+}
+
+func TestDecodeColumnsHugeInt(t *testing.T) {
+	schema := []TableElement{{ColumnName: "total", ColumnType: MDB_HUGEINT}}
+	cols, err := DecodeColumns(schema, []string{"[ 170141183460469231731687303715884105727\t]", "[ NULL\t]"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cols[0].Kind != KindBigInt {
+		t.Fatalf("Invalid kind: %v, expected: %v", cols[0].Kind, KindBigInt)
+	}
+	if cols[0].BigInt[0].String() != "170141183460469231731687303715884105727" {
+		t.Errorf("Invalid value: %v", cols[0].BigInt[0])
+	}
+	if cols[0].BigInt[1] != nil || !cols[0].Nulls.IsNull(1) {
+		t.Errorf("Invalid NULL value: %v", cols[0].BigInt[1])
+	}
+}
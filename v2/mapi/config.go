@@ -6,6 +6,7 @@ package mapi
 
 import (
 	"fmt"
+	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
@@ -17,6 +18,8 @@ type config struct {
 	Hostname string
 	Database string
 	Port     int
+
+	AutoCommit bool
 }
 
 func parseDSN(name string) (config, error) {
@@ -25,12 +28,13 @@ func parseDSN(name string) (config, error) {
 	if ipv6_re.MatchString(name) {
 		m := ipv6_re.FindAllStringSubmatch(name, -1)[0]
 		n := ipv6_re.SubexpNames()
-		return getConfig(m, n, true), nil
+		return parseOptions(getConfig(m, n, true))
 	}
 
 	c := config{
 		Hostname: "localhost",
 		Port:     50000,
+		AutoCommit: true,
 	}
 
 	reversed := reverse(name)
@@ -49,7 +53,36 @@ func parseDSN(name string) (config, error) {
 		return config{}, fmt.Errorf("mapi: invalid DSN")
 	}
 
-	return newConfig, nil
+	return parseOptions(newConfig)
+}
+
+// parseOptions parses the options that can follow the database name in the
+// DSN, for example "localhost/testdb?autocommit=false".
+func parseOptions(c config) (config, error) {
+	database, options, found := Cut(c.Database, "?")
+	c.Database = database
+	if !found {
+		return c, nil
+	}
+
+	values, err := url.ParseQuery(options)
+	if err != nil {
+		return config{}, fmt.Errorf("mapi: invalid DSN")
+	}
+	for name, v := range values {
+		value := v[len(v)-1]
+		switch name {
+		case "autocommit":
+			c.AutoCommit, err = strconv.ParseBool(value)
+		default:
+			return config{}, fmt.Errorf("mapi: unknown DSN option: %s", name)
+		}
+		if err != nil {
+			return config{}, fmt.Errorf("mapi: invalid value for DSN option %s: %s", name, value)
+		}
+	}
+
+	return c, nil
 }
 
 func parseCreds(creds string, c config) (config, error) {
@@ -105,6 +138,7 @@ func getConfig(m []string, n []string, ipv6 bool) config {
 	c := config{
 		Hostname: "localhost",
 		Port:     50000,
+		AutoCommit: true,
 	}
 	for i, v := range m {
 		if n[i] == "username" {
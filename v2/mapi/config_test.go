@@ -100,3 +100,49 @@ func TestParseIpv6DSN(t *testing.T) {
 	}
 
 }
+
+func TestParseDSNOptions(t *testing.T) {
+	t.Run("Verify default options", func(t *testing.T) {
+		c, err := parseDSN("me:secret@localhost:1234/testdb")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !c.AutoCommit {
+			t.Error("Autocommit is not enabled by default")
+		}
+	})
+
+	t.Run("Verify autocommit option", func(t *testing.T) {
+		c, err := parseDSN("me:secret@localhost:1234/testdb?autocommit=false")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.AutoCommit {
+			t.Error("Autocommit is not disabled")
+		}
+		if c.Database != "testdb" {
+			t.Errorf("Invalid database: %s, expected: %s", c.Database, "testdb")
+		}
+	})
+
+	t.Run("Verify options with ipv6 address", func(t *testing.T) {
+		c, err := parseDSN("me:secret@[::1]:1234/testdb?autocommit=false")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.AutoCommit || c.Database != "testdb" {
+			t.Errorf("Invalid config: %+v", c)
+		}
+	})
+
+	t.Run("Verify invalid options", func(t *testing.T) {
+		for _, n := range []string{
+			"localhost/testdb?autocommit=maybe",
+			"localhost/testdb?unknown=1",
+		} {
+			if _, err := parseDSN(n); err == nil {
+				t.Errorf("Error parsing invalid DSN: %s", n)
+			}
+		}
+	})
+}
@@ -5,7 +5,11 @@
 package mapi
 
 import (
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"math/big"
 	"reflect"
 	"strconv"
 	"strings"
@@ -35,6 +39,7 @@ const (
 
 	MDB_MONTH_INTERVAL = "month_interval"
 	MDB_SEC_INTERVAL   = "sec_interval"
+	MDB_DAY_INTERVAL   = "day_interval"
 	MDB_WRD            = "wrd"
 	MDB_TINYINT        = "tinyint"
 
@@ -44,6 +49,14 @@ const (
 	MDB_LONGINT     = "longint"
 	MDB_FLOAT       = "float"
 	MDB_TIMESTAMPTZ = "timestamptz"
+	MDB_TIMETZ      = "timetz"
+	MDB_UUID        = "uuid"
+	MDB_JSON        = "json"
+	MDB_INET        = "inet"
+	MDB_URL         = "url"
+	MDB_GEOMETRY    = "geometry"
+	MDB_GEOMETRYA   = "geometrya"
+	MDB_MBR         = "mbr"
 
 	// full names and aliases, spaces are replaced with underscores
 	//lint:ignore U1000 prepare to enable staticchecks
@@ -67,9 +80,17 @@ var timeFormats = []string{
 	"2006-01-02 15:04:05 -0700 MST",
 	"Mon Jan 2 15:04:05 -0700 MST 2006",
 	"2006-01-02 15:04:05.999999+00:00",
+	"2006-01-02 15:04:05-07:00",
 	"15:04:05",
+	"15:04:05-07:00",
 }
 
+// timestampFormat is the format of time.Time parameters. The server keeps
+// at most microseconds, so the fraction of the second is truncated to six
+// digits. The offset of the time zone is sent, so the server reads the
+// value in the right zone.
+const timestampFormat = "2006-01-02 15:04:05.999999-07:00"
+
 type toGoConverter func(string) (Value, error)
 type toMonetConverter func(Value) (string, error)
 
@@ -95,13 +116,39 @@ func unquote(s string) (string, error) {
 		return s, nil
 	}
 
-	var runeTmp [utf8.UTFMax]byte
 	buf := make([]byte, 0, 3*len(s)/2) // Try to avoid more allocations.
+	buf, err := appendUnquoted(buf, s)
+	if err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+// appendUnquoted appends the text of a quoted value, without the quotes, to
+// buf and resolves the escape sequences of the server, like \n, \t, \\ and
+// \ddd octal bytes.
+func appendUnquoted(buf []byte, s string) ([]byte, error) {
+	if !contains(s, '\\') {
+		return append(buf, s...), nil
+	}
+
+	var runeTmp [utf8.UTFMax]byte
 	for len(s) > 0 {
-		c, multibyte, ss, err := strconv.UnquoteChar(s, '\'')
+		// The server escapes double quotes, but both quotes are accepted
+		// escaped and unescaped
+		if strings.HasPrefix(s, "\\'") {
+			buf = append(buf, '\'')
+			s = s[2:]
+			continue
+		}
+		if s[0] == '"' {
+			buf = append(buf, '"')
+			s = s[1:]
+			continue
+		}
+		c, multibyte, ss, err := strconv.UnquoteChar(s, '"')
 		if err != nil {
-			fmt.Printf("E: %v\n -> %s\n", err, s)
-			return "", err
+			return buf, err
 		}
 		s = ss
 		if c < utf8.RuneSelf || !multibyte {
@@ -111,11 +158,69 @@ func unquote(s string) (string, error) {
 			buf = append(buf, runeTmp[:n]...)
 		}
 	}
-	return string(buf), nil
+	return buf, nil
+}
+
+// textTypes are the types of which the values are sent as quoted text and
+// are converted to a string.
+var textTypes = map[string]bool{
+	MDB_CHAR:    true,
+	MDB_VARCHAR: true,
+	MDB_CLOB:    true,
+	MDB_JSON:    true,
+}
+
+// IsText reports whether the values of the type are text.
+func IsText(dataType string) bool {
+	return textTypes[dataType]
 }
 
+// AppendText appends the text of a field of a text column to dst. The
+// quotes are removed and escaped characters are converted, like the string
+// that ConvertValue returns. When dst has enough capacity, nothing is
+// allocated.
+func AppendText(dst []byte, value string) ([]byte, error) {
+	v := strings.TrimSpace(value)
+	if len(v) < 2 {
+		return dst, fmt.Errorf("mapi: invalid text value %q", value)
+	}
+	return appendUnquoted(dst, strings.TrimSpace(v[1:len(v)-1]))
+}
+
+// rawTypes are the types of which the values are returned to database/sql
+// as their text. The Go type of such a column implements sql.Scanner, so
+// the value can be scanned into that type without loss, and also into a
+// string.
+var rawTypes = map[string]bool{
+	MDB_DECIMAL: true,
+}
+
+// IsRaw reports whether the values of the type are returned as their text.
+func IsRaw(dataType string) bool {
+	return rawTypes[dataType]
+}
+
+// AppendRaw appends the text of a field of a column with a raw type to dst.
+// The quotes of a quoted value are removed.
+func AppendRaw(dst []byte, value string) ([]byte, error) {
+	v := strings.TrimSpace(value)
+	if len(v) >= 2 && v[0] == '"' && v[len(v)-1] == '"' {
+		return AppendText(dst, v)
+	}
+	return append(dst, v...), nil
+}
+
+// toByteArray decodes a blob value, which the server sends as hexadecimal
+// text.
 func toByteArray(v string) (Value, error) {
-	return []byte(v[1 : len(v)-1]), nil
+	if len(v) >= 2 && (v[0] == '"' || v[0] == '\'') && v[len(v)-1] == v[0] {
+		v = v[1 : len(v)-1]
+	}
+	b, err := hex.DecodeString(v)
+	if err != nil {
+		return nil, fmt.Errorf("mapi: invalid blob value: %v", err)
+	}
+	return b, nil
 }
 
 func toDouble(v string) (Value, error) {
@@ -169,6 +274,15 @@ func toInt64(v string) (Value, error) {
 	return r, err
 }
 
+// toHugeInt decodes a 128 bit integer, which does not fit in an int64.
+func toHugeInt(v string) (Value, error) {
+	i, ok := new(big.Int).SetString(v, 10)
+	if !ok {
+		return nil, fmt.Errorf("mapi: invalid hugeint value %q", v)
+	}
+	return i, nil
+}
+
 func parseTime(v string) (t time.Time, err error) {
 	for _, f := range timeFormats {
 		t, err = time.Parse(f, v)
@@ -180,7 +294,7 @@ func parseTime(v string) (t time.Time, err error) {
 }
 
 func toNil(v string) (Value, error) {
-	return "NULL", nil
+	return nil, nil
 }
 
 func toBool(v string) (Value, error) {
@@ -197,12 +311,22 @@ func toDate(v string) (Value, error) {
 }
 
 func toTime(v string) (Value, error) {
+	t, err := parseTime(v)
+	if err != nil {
+		return nil, err
+	}
+	return GetTime(t), nil
+}
+
+// toTimeTz decodes a time with a time zone. The date is set to January 1,
+// 1970, like Time.Time does, and the location has the offset of the value.
+func toTimeTz(v string) (Value, error) {
 	t, err := parseTime(v)
 	if err != nil {
 		return nil, err
 	}
 	hour, min, sec := t.Clock()
-	return Time{hour, min, sec}, nil
+	return time.Date(1970, time.January, 1, hour, min, sec, t.Nanosecond(), t.Location()), nil
 }
 func toTimestamp(v string) (Value, error) {
 	return parseTime(v)
@@ -216,13 +340,13 @@ var toGoMappers = map[string]toGoConverter{
 	MDB_VARCHAR:        strip,
 	MDB_CLOB:           strip,
 	MDB_BLOB:           toByteArray,
-	MDB_DECIMAL:        toDouble,
+	MDB_DECIMAL:        toDecimal,
 	MDB_NULL:           toNil,
 	MDB_SMALLINT:       toInt16,
 	MDB_INT:            toInt32,
 	MDB_WRD:            toInt32,
 	MDB_BIGINT:         toInt64,
-	MDB_HUGEINT:        toInt64,
+	MDB_HUGEINT:        toHugeInt,
 	MDB_SERIAL:         toInt64,
 	MDB_REAL:           toFloat,
 	MDB_DOUBLE:         toDouble,
@@ -231,14 +355,23 @@ var toGoMappers = map[string]toGoConverter{
 	MDB_TIME:           toTime,
 	MDB_TIMESTAMP:      toTimestamp,
 	MDB_TIMESTAMPTZ:    toTimestampTz,
-	MDB_INTERVAL:       strip,
-	MDB_MONTH_INTERVAL: strip,
-	MDB_SEC_INTERVAL:   strip,
+	MDB_TIMETZ:         toTimeTz,
+	MDB_INTERVAL:       toDuration,
+	MDB_MONTH_INTERVAL: toMonthInterval,
+	MDB_SEC_INTERVAL:   toDuration,
+	MDB_DAY_INTERVAL:   toDuration,
 	MDB_TINYINT:        toInt8,
 	MDB_SHORTINT:       toInt16,
 	MDB_MEDIUMINT:      toInt32,
 	MDB_LONGINT:        toInt64,
 	MDB_FLOAT:          toFloat,
+	MDB_UUID:           toUUID,
+	MDB_JSON:           toJSON,
+	MDB_INET:           toInet,
+	MDB_URL:            toURL,
+	MDB_GEOMETRY:       toGeometry,
+	MDB_GEOMETRYA:      toGeometry,
+	MDB_MBR:            toGeometry,
 }
 
 func toString(v Value) (string, error) {
@@ -256,10 +389,51 @@ func toNull(v Value) (string, error) {
 	return "NULL", nil
 }
 
+func toBigIntString(v Value) (string, error) {
+	switch val := v.(type) {
+	case *big.Int:
+		if val == nil {
+			return toNull(nil)
+		}
+		return val.String(), nil
+	case big.Int:
+		return val.String(), nil
+	default:
+		return "", fmt.Errorf("mapi: unsupported type")
+	}
+}
+
+// toJSON decodes a json value, which the server sends as a quoted string.
+func toJSON(v string) (Value, error) {
+	s, err := strip(v)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(s.(string)), nil
+}
+
+func toJSONString(v Value) (string, error) {
+	switch val := v.(type) {
+	case json.RawMessage:
+		if val == nil {
+			return toNull(nil)
+		}
+		if !json.Valid(val) {
+			return "", fmt.Errorf("mapi: invalid json value")
+		}
+		return toQuotedString(string(val))
+	default:
+		return "", fmt.Errorf("mapi: unsupported type")
+	}
+}
+
 func toByteString(v Value) (string, error) {
 	switch val := v.(type) {
 	case []uint8:
-		return toQuotedString(string(val))
+		if val == nil {
+			return toNull(nil)
+		}
+		return fmt.Sprintf("BLOB '%s'", hex.EncodeToString(val)), nil
 	default:
 		return "", fmt.Errorf("unsupported type")
 	}
@@ -268,9 +442,11 @@ func toByteString(v Value) (string, error) {
 func toDateTimeString(v Value) (string, error) {
 	switch val := v.(type) {
 	case Time:
-		return toQuotedString(fmt.Sprintf("%02d:%02d:%02d", val.Hour, val.Min, val.Sec))
+		return toQuotedString(val.String())
 	case Date:
-		return toQuotedString(fmt.Sprintf("%04d-%02d-%02d", val.Year, val.Month, val.Day))
+		return toQuotedString(val.String())
+	case time.Time:
+		return toQuotedString(val.Format(timestampFormat))
 	default:
 		return "", fmt.Errorf("mapi: unsupported type")
 	}
@@ -290,9 +466,22 @@ var toMonetMappers = map[string]toMonetConverter{
 	"nil":          toNull,
 	"null":         toNull,
 	"[]uint8":      toByteString,
-	"time.Time":    toQuotedString,
+	"time.Time":    toDateTimeString,
 	"mapi.Time": toDateTimeString,
 	"mapi.Date": toDateTimeString,
+	"mapi.Decimal": toDecimalString,
+	"*big.Int":     toBigIntString,
+	"big.Int":      toBigIntString,
+	"mapi.UUID":    toUUIDString,
+	"[16]uint8":    toUUIDString,
+	"time.Duration":      toDurationString,
+	"mapi.MonthInterval": toMonthIntervalString,
+	"mapi.Geometry":      toGeometryString,
+	"netip.Addr":   toInetString,
+	"netip.Prefix": toInetString,
+	"*url.URL":     toURLString,
+	"url.URL":      toURLString,
+	reflect.TypeOf(json.RawMessage{}).String(): toJSONString,
 }
 
 func convertToGo(value, dataType string) (Value, error) {
@@ -307,6 +496,15 @@ func convertToGo(value, dataType string) (Value, error) {
 	return nil, fmt.Errorf("mapi: type not supported: %s", dataType)
 }
 
+// ConvertValue converts a field of a row, as it is sent by the server, to
+// the Go value of the type.
+func ConvertValue(value, dataType string) (Value, error) {
+	return convertToGo(value, dataType)
+}
+
+// ConvertToMonet converts a value to its SQL literal. A nil value, including
+// a nil pointer, is converted to NULL. Pointers are dereferenced and the
+// value of a driver.Valuer, for example sql.NullString, is converted.
 func ConvertToMonet(value Value) (string, error) {
 	t := reflect.TypeOf(value)
 	n := "nil"
@@ -317,5 +515,41 @@ func ConvertToMonet(value Value) (string, error) {
 	if mapper, ok := toMonetMappers[n]; ok {
 		return mapper(value)
 	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		// A pointer to a supported type, that can also be a driver.Valuer
+		if _, ok := toMonetMappers[t.Elem().String()]; ok {
+			return ConvertToMonet(rv.Elem().Interface())
+		}
+	}
+	if v, ok := value.(driver.Valuer); ok {
+		return valuerToMonet(v)
+	}
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return toNull(nil)
+		}
+		return ConvertToMonet(rv.Elem().Interface())
+	}
 	return "", fmt.Errorf("mapi: type not supported: %v", t)
 }
+
+// valuerToMonet converts the value of a driver.Valuer. Like the sql package,
+// a nil pointer is converted to NULL when the Value method has a value
+// receiver, because the method can not be called.
+func valuerToMonet(v driver.Valuer) (string, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && rv.IsNil() && rv.Type().Elem().Implements(valuerType) {
+		return toNull(nil)
+	}
+	val, err := v.Value()
+	if err != nil {
+		return "", err
+	}
+	if _, ok := val.(driver.Valuer); ok {
+		return "", fmt.Errorf("mapi: Value of %T returned a driver.Valuer", v)
+	}
+	return ConvertToMonet(val)
+}
+
+var valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
@@ -6,6 +6,13 @@ package mapi
 
 import (
 	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"math/big"
+	"net/netip"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
 )
@@ -31,11 +38,21 @@ func TestConvertToMonet(t *testing.T) {
 		{true, "true"},
 		{false, "false"},
 		{nil, "NULL"},
-		{[]byte{1, 2, 3}, "'" + string([]byte{1, 2, 3}) + "'"},
-		{Time{10, 20, 30}, "'10:20:30'"},
+		{[]byte{1, 2, 3}, "BLOB '010203'"},
+		{[]byte{}, "BLOB ''"},
+		{Time{10, 20, 30, 0}, "'10:20:30'"},
+		{Time{10, 20, 30, 123456000}, "'10:20:30.123456'"},
+		{Time{10, 20, 30, 123456789}, "'10:20:30.123456'"},
+		{Time{10, 20, 30, 999}, "'10:20:30'"},
 		{Date{2001, time.January, 2}, "'2001-01-02'"},
 		{time.Date(2001, time.January, 2, 10, 20, 30, 0, time.FixedZone("CET", 3600)),
-			"'2001-01-02 10:20:30 +0100 CET'"},
+			"'2001-01-02 10:20:30+01:00'"},
+		{time.Date(2001, time.January, 2, 10, 20, 30, 123456789, time.UTC),
+			"'2001-01-02 10:20:30.123456+00:00'"},
+		{time.Date(2001, time.January, 2, 10, 20, 30, 500000999, time.FixedZone("CET", 3600)),
+			"'2001-01-02 10:20:30.5+01:00'"},
+		{time.Date(2001, time.January, 2, 10, 20, 30, 999, time.UTC),
+			"'2001-01-02 10:20:30+00:00'"},
 	}
 
 	for _, c := range tcs {
@@ -62,15 +79,14 @@ func TestConvertToGo(t *testing.T) {
 		{"32", "mediumint", int32(32)},
 		{"64", "bigint", int64(64)},
 		{"64", "longint", int64(64)},
-		{"64", "hugeint", int64(64)},
 		{"64", "serial", int64(64)},
 		{"3.2", "float", float32(3.2)},
 		{"3.2", "real", float32(3.2)},
 		{"6.4", "double", float64(6.4)},
-		{"6.4", "decimal", float64(6.4)},
 		{"true", "boolean", true},
 		{"false", "boolean", false},
-		{"10:20:30", "time", Time{10, 20, 30}},
+		{"10:20:30", "time", Time{10, 20, 30, 0}},
+		{"10:20:30.000123", "time", Time{10, 20, 30, 123000}},
 		{"2001-01-02", "date", Date{2001, time.January, 2}},
 		{"'string'", "char", "string"},
 		{"'string'", "varchar", "string"},
@@ -78,7 +94,17 @@ func TestConvertToGo(t *testing.T) {
 		{"'quoted \\'string\\''", "char", "quoted 'string'"},
 		{"'quoted \\\\\\'string\\\\\\''", "char", "quoted \\'string\\'"},
 		{"'back\\\\slashed'", "char", "back\\slashed"},
-		{"'ABC'", "blob", []uint8{0x41, 0x42, 0x43}},
+		{"414243", "blob", []uint8{0x41, 0x42, 0x43}},
+		{"\"00FF7f\"", "blob", []uint8{0x00, 0xff, 0x7f}},
+		{"", "blob", []uint8{}},
+		{"NULL", "varchar", nil},
+		{"NULL", "int", nil},
+		{"NULL", "boolean", nil},
+		{"NULL", "timestamp", nil},
+		{" NULL", "blob", nil},
+		{"NULL", "NULL", nil},
+		{"6.4", "decimal", Decimal{unscaled: big.NewInt(64), scale: 1}},
+		{"-0.0100", "decimal", Decimal{unscaled: big.NewInt(-100), scale: 4}},
 	}
 
 	for _, c := range tcs {
@@ -90,6 +116,9 @@ func TestConvertToGo(t *testing.T) {
 			switch val := v.(type) {
 			case []byte:
 				ok = compareByteArray(t, val, c.e)
+			case Decimal:
+				e, isDecimal := c.e.(Decimal)
+				ok = isDecimal && val.Scale() == e.Scale() && val.Unscaled().Cmp(e.Unscaled()) == 0
 			default:
 				ok = v == c.e
 			}
@@ -100,6 +129,12 @@ func TestConvertToGo(t *testing.T) {
 	}
 }
 
+func TestConvertToGoInvalidBlob(t *testing.T) {
+	if _, err := convertToGo("ABC", "blob"); err == nil {
+		t.Error("Invalid hexadecimal value did not fail")
+	}
+}
+
 func compareByteArray(t *testing.T, val []byte, e Value) bool {
 	switch exp := e.(type) {
 	case []byte:
@@ -108,3 +143,306 @@ func compareByteArray(t *testing.T, val []byte, e Value) bool {
 		return false
 	}
 }
+
+func TestAppendText(t *testing.T) {
+	type tc struct {
+		v string
+		e string
+	}
+	var tcs = []tc{
+		{"\"string\"", "string"},
+		{" \"string\"", "string"},
+		{"\"quoted \\'string\\'\"", "quoted 'string'"},
+		{"\"back\\\\slashed\"", "back\\slashed"},
+		{"\"\"", ""},
+		{"\"double \\\"quoted\\\"\"", "double \"quoted\""},
+		{"\"unescaped ' and \"\"", "unescaped ' and \""},
+		{"\"tab\\tand\\nnewline\"", "tab\tand\nnewline"},
+		{"\"octal \\101\"", "octal A"},
+		{"\"caf\u00e9 \\u00e9\"", "caf\u00e9 \u00e9"},
+	}
+
+	buf := []byte("prefix")
+	for _, c := range tcs {
+		b, err := AppendText(buf, c.v)
+		if err != nil {
+			t.Errorf("Error converting value: %v -> %v", c.v, err)
+		} else if string(b) != "prefix"+c.e {
+			t.Errorf("Invalid value: %s, expected: %s", b[len(buf):], c.e)
+		}
+	}
+}
+
+type testValuer struct {
+	v string
+}
+
+func (v testValuer) Value() (driver.Value, error) {
+	return v.v, nil
+}
+
+func TestConvertToMonetNull(t *testing.T) {
+	var nilString *string
+	var nilTime *time.Time
+	var nilValuer *testValuer
+	var nilBytes []byte
+	s := "string"
+	i := int64(64)
+
+	type tc struct {
+		v Value
+		e string
+	}
+	var tcs = []tc{
+		{nilString, "NULL"},
+		{nilTime, "NULL"},
+		{nilValuer, "NULL"},
+		{nilBytes, "NULL"},
+		{&s, "'string'"},
+		{&i, "64"},
+		{sql.NullString{}, "NULL"},
+		{sql.NullString{String: "string", Valid: true}, "'string'"},
+		{sql.NullInt64{}, "NULL"},
+		{sql.NullInt64{Int64: 64, Valid: true}, "64"},
+		{sql.NullFloat64{Float64: 6.4, Valid: true}, "6.4"},
+		{sql.NullBool{}, "NULL"},
+		{sql.NullTime{}, "NULL"},
+		{&sql.NullInt32{Int32: 32, Valid: true}, "32"},
+		{testValuer{"valuer"}, "'valuer'"},
+	}
+
+	for _, c := range tcs {
+		s, err := ConvertToMonet(c.v)
+		if err != nil {
+			t.Errorf("Error converting value: %v -> %v", c.v, err)
+		} else if s != c.e {
+			t.Errorf("Invalid value: %s, expected: %s", s, c.e)
+		}
+	}
+}
+
+func TestHugeInt(t *testing.T) {
+	type tc struct {
+		v string
+		e string
+	}
+	var tcs = []tc{
+		{"64", "64"},
+		{"-170141183460469231731687303715884105728", "-170141183460469231731687303715884105728"},
+		{"170141183460469231731687303715884105727", "170141183460469231731687303715884105727"},
+	}
+
+	for _, c := range tcs {
+		v, err := convertToGo(c.v, "hugeint")
+		if err != nil {
+			t.Errorf("Error converting value: %v -> %v", c.v, err)
+			continue
+		}
+		i, ok := v.(*big.Int)
+		if !ok || i.String() != c.e {
+			t.Errorf("Invalid value: %v, expected: %s", v, c.e)
+			continue
+		}
+		s, err := ConvertToMonet(i)
+		if err != nil || s != c.e {
+			t.Errorf("Invalid value: %s, expected: %s", s, c.e)
+		}
+	}
+
+	if _, err := convertToGo("1.5", "hugeint"); err == nil {
+		t.Error("Invalid hugeint did not fail")
+	}
+	var nilInt *big.Int
+	if s, err := ConvertToMonet(nilInt); err != nil || s != "NULL" {
+		t.Errorf("Invalid value: %s, expected: NULL", s)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	v, err := convertToGo(`"{\"name\": \"it's\", \"path\": \"c:\\\\tmp\"}"`, "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, ok := v.(json.RawMessage)
+	if !ok || string(raw) != `{"name": "it's", "path": "c:\\tmp"}` {
+		t.Errorf("Invalid value: %s", v)
+	}
+
+	s, err := ConvertToMonet(json.RawMessage(`{"name": "it's", "path": "c:\\tmp"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != `'{"name": "it\'s", "path": "c:\\\\tmp"}'` {
+		t.Errorf("Invalid value: %s", s)
+	}
+
+	if _, err := ConvertToMonet(json.RawMessage(`{"name": `)); err == nil {
+		t.Error("Invalid json did not fail")
+	}
+	if s, err := ConvertToMonet(json.RawMessage(nil)); err != nil || s != "NULL" {
+		t.Errorf("Invalid value: %s, expected: NULL", s)
+	}
+}
+
+func TestInet(t *testing.T) {
+	type tc struct {
+		v string
+		e netip.Prefix
+	}
+	var tcs = []tc{
+		{"192.168.1.5", netip.MustParsePrefix("192.168.1.5/32")},
+		{"10.0.0.0/8", netip.MustParsePrefix("10.0.0.0/8")},
+		{"10.1.2.3/8", netip.MustParsePrefix("10.1.2.3/8")},
+		{`"2001:db8::/32"`, netip.MustParsePrefix("2001:db8::/32")},
+	}
+
+	for _, c := range tcs {
+		v, err := convertToGo(c.v, "inet")
+		if err != nil {
+			t.Errorf("Error converting value: %v -> %v", c.v, err)
+			continue
+		}
+		if p, ok := v.(netip.Prefix); !ok || p != c.e {
+			t.Errorf("Invalid value: %v, expected: %v", v, c.e)
+		}
+	}
+
+	if _, err := convertToGo("10.0.0.256", "inet"); err == nil {
+		t.Error("Invalid inet did not fail")
+	}
+	if s, err := ConvertToMonet(netip.MustParseAddr("192.168.1.5")); err != nil || s != "inet '192.168.1.5'" {
+		t.Errorf("Invalid value: %s, %v", s, err)
+	}
+	if s, err := ConvertToMonet(netip.MustParsePrefix("10.1.2.3/8")); err != nil || s != "inet '10.1.2.3/8'" {
+		t.Errorf("Invalid value: %s, %v", s, err)
+	}
+	if _, err := ConvertToMonet(netip.Addr{}); err == nil {
+		t.Error("Invalid address did not fail")
+	}
+}
+
+func TestURL(t *testing.T) {
+	v, err := convertToGo(`"https://example.com:8080/a/b?q=it\'s#top"`, "url")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, ok := v.(*url.URL)
+	if !ok || u.Host != "example.com:8080" || u.RawQuery != "q=it's" || u.Fragment != "top" {
+		t.Errorf("Invalid value: %v", v)
+	}
+
+	s, err := ConvertToMonet(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != `url 'https://example.com:8080/a/b?q=it\'s#top'` {
+		t.Errorf("Invalid value: %s", s)
+	}
+	var nilURL *url.URL
+	if s, err := ConvertToMonet(nilURL); err != nil || s != "NULL" {
+		t.Errorf("Invalid value: %s, expected: NULL", s)
+	}
+}
+
+func TestTemporal(t *testing.T) {
+	cet := time.FixedZone("", 3600)
+	ist := time.FixedZone("", 5*3600+1800)
+	type tc struct {
+		v string
+		t string
+		e time.Time
+	}
+	var tcs = []tc{
+		{"2001-01-02 10:20:30", "timestamp", time.Date(2001, time.January, 2, 10, 20, 30, 0, time.UTC)},
+		{"2001-01-02 10:20:30.123", "timestamp", time.Date(2001, time.January, 2, 10, 20, 30, 123000000, time.UTC)},
+		{"2001-01-02 10:20:30.123456", "timestamp", time.Date(2001, time.January, 2, 10, 20, 30, 123456000, time.UTC)},
+		{"2001-01-02 10:20:30.123456+00:00", "timestamptz", time.Date(2001, time.January, 2, 10, 20, 30, 123456000, time.UTC)},
+		{"2001-01-02 10:20:30.5+01:00", "timestamptz", time.Date(2001, time.January, 2, 10, 20, 30, 500000000, cet)},
+		{"2001-01-02 10:20:30+05:30", "timestamptz", time.Date(2001, time.January, 2, 10, 20, 30, 0, ist)},
+		{"10:20:30+01:00", "timetz", time.Date(1970, time.January, 1, 10, 20, 30, 0, cet)},
+		{"10:20:30.000001-00:00", "timetz", time.Date(1970, time.January, 1, 10, 20, 30, 1000, time.UTC)},
+	}
+
+	for _, c := range tcs {
+		v, err := convertToGo(c.v, c.t)
+		if err != nil {
+			t.Errorf("Error converting value: %v (%s) -> %v", c.v, c.t, err)
+			continue
+		}
+		tm, ok := v.(time.Time)
+		if !ok || !tm.Equal(c.e) {
+			t.Errorf("Invalid value: %v (%v - %s), expected: %v", v, c.v, c.t, c.e)
+			continue
+		}
+		_, offset := tm.Zone()
+		_, expected := c.e.Zone()
+		if c.t != "timestamp" && offset != expected {
+			t.Errorf("Invalid offset: %v (%v - %s), expected: %v", v, c.v, c.t, c.e)
+		}
+
+		// The parameter is parsed back into the same time
+		s, err := ConvertToMonet(tm)
+		if err != nil {
+			t.Fatal(err)
+		}
+		back, err := convertToGo(strings.Trim(s, "'"), "timestamptz")
+		if err != nil {
+			t.Errorf("Error converting value: %v -> %v", s, err)
+		} else if !back.(time.Time).Equal(tm) {
+			t.Errorf("Invalid value: %v (%s), expected: %v", back, s, tm)
+		}
+	}
+
+	v, err := convertToGo("23:59:59.999999", "time")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != (Time{23, 59, 59, 999999000}) {
+		t.Errorf("Invalid value: %v", v)
+	}
+	if s, err := ConvertToMonet(v); err != nil || s != "'23:59:59.999999'" {
+		t.Errorf("Invalid value: %s, %v", s, err)
+	}
+}
+
+func TestInterval(t *testing.T) {
+	type tc struct {
+		v string
+		t string
+		e Value
+		s string
+	}
+	var tcs = []tc{
+		{"3600.000", "sec_interval", time.Hour, "INTERVAL '3600' SECOND"},
+		{"1.500", "sec_interval", 1500 * time.Millisecond, "INTERVAL '1.5' SECOND"},
+		{"-0.001", "sec_interval", -time.Millisecond, "INTERVAL '-0.001' SECOND"},
+		{"172800.000", "day_interval", 48 * time.Hour, "INTERVAL '172800' SECOND"},
+		{"14", "month_interval", MonthInterval(14), "INTERVAL '14' MONTH"},
+		{"-3", "month_interval", MonthInterval(-3), "INTERVAL '-3' MONTH"},
+	}
+
+	for _, c := range tcs {
+		v, err := convertToGo(c.v, c.t)
+		if err != nil {
+			t.Errorf("Error converting value: %v (%s) -> %v", c.v, c.t, err)
+			continue
+		}
+		if v != c.e {
+			t.Errorf("Invalid value: %v (%v - %s), expected: %v", v, c.v, c.t, c.e)
+		}
+		s, err := ConvertToMonet(v)
+		if err != nil || s != c.s {
+			t.Errorf("Invalid value: %s, expected: %s", s, c.s)
+		}
+	}
+
+	if s, err := ConvertToMonet(time.Duration(-1)); err != nil || s != "INTERVAL '-0.000000001' SECOND" {
+		t.Errorf("Invalid value: %s, %v", s, err)
+	}
+	if _, err := convertToGo("1 day", "sec_interval"); err == nil {
+		t.Error("Invalid interval did not fail")
+	}
+	if _, err := convertToGo("99999999999.000", "sec_interval"); err == nil {
+		t.Error("Interval out of range did not fail")
+	}
+}
@@ -0,0 +1,142 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package mapi
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal is an exact decimal number, the value of a DECIMAL or NUMERIC
+// column. It is stored as an unscaled integer and a scale, the number of
+// digits after the decimal point, so no precision is lost. The zero value
+// is 0.
+type Decimal struct {
+	unscaled *big.Int
+	scale    int
+}
+
+// NewDecimal returns the decimal unscaled * 10^-scale.
+func NewDecimal(unscaled *big.Int, scale int) Decimal {
+	return Decimal{unscaled: new(big.Int).Set(unscaled), scale: scale}
+}
+
+// ParseDecimal parses a decimal number, for example "-123.4500". The scale
+// of the result is the number of digits after the decimal point.
+func ParseDecimal(s string) (Decimal, error) {
+	digits := strings.TrimLeft(s, "+-")
+	if len(s)-len(digits) > 1 {
+		return Decimal{}, fmt.Errorf("mapi: invalid decimal %q", s)
+	}
+	intPart, fracPart := digits, ""
+	if i := strings.IndexByte(digits, '.'); i >= 0 {
+		intPart, fracPart = digits[:i], digits[i+1:]
+	}
+	if intPart+fracPart == "" || !isDigits(intPart) || !isDigits(fracPart) {
+		return Decimal{}, fmt.Errorf("mapi: invalid decimal %q", s)
+	}
+
+	unscaled, _ := new(big.Int).SetString(intPart+fracPart, 10)
+	if strings.HasPrefix(s, "-") {
+		unscaled.Neg(unscaled)
+	}
+	return Decimal{unscaled: unscaled, scale: len(fracPart)}, nil
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Unscaled returns the value of the decimal without the decimal point.
+func (d Decimal) Unscaled() *big.Int {
+	if d.unscaled == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(d.unscaled)
+}
+
+// Scale returns the number of digits after the decimal point.
+func (d Decimal) Scale() int {
+	return d.scale
+}
+
+// Rat returns the exact value of the decimal as a rational number.
+func (d Decimal) Rat() *big.Rat {
+	denom := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(d.scale)), nil)
+	return new(big.Rat).SetFrac(d.Unscaled(), denom)
+}
+
+// Float64 returns the nearest float64 value of the decimal.
+func (d Decimal) Float64() float64 {
+	f, _ := d.Rat().Float64()
+	return f
+}
+
+// String returns the decimal with all the digits of its scale.
+func (d Decimal) String() string {
+	u := d.Unscaled()
+	digits := new(big.Int).Abs(u).String()
+	sign := ""
+	if u.Sign() < 0 {
+		sign = "-"
+	}
+	if d.scale <= 0 {
+		return sign + digits
+	}
+	if len(digits) <= d.scale {
+		digits = strings.Repeat("0", d.scale-len(digits)+1) + digits
+	}
+	i := len(digits) - d.scale
+	return sign + digits[:i] + "." + digits[i:]
+}
+
+// Scan implements the sql.Scanner interface. The driver returns DECIMAL
+// values as text, so they are parsed without loss of precision.
+func (d *Decimal) Scan(src any) error {
+	var err error
+	switch v := src.(type) {
+	case Decimal:
+		*d = NewDecimal(v.Unscaled(), v.scale)
+	case string:
+		*d, err = ParseDecimal(v)
+	case []byte:
+		*d, err = ParseDecimal(string(v))
+	case int64:
+		*d = Decimal{unscaled: big.NewInt(v)}
+	case float64:
+		*d, err = ParseDecimal(strconv.FormatFloat(v, 'f', -1, 64))
+	case nil:
+		err = fmt.Errorf("mapi: cannot scan NULL into Decimal")
+	default:
+		err = fmt.Errorf("mapi: cannot scan %T into Decimal", src)
+	}
+	return err
+}
+
+// Value implements the driver.Valuer interface.
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+func toDecimal(v string) (Value, error) {
+	return ParseDecimal(v)
+}
+
+func toDecimalString(v Value) (string, error) {
+	switch val := v.(type) {
+	case Decimal:
+		return val.String(), nil
+	default:
+		return "", fmt.Errorf("mapi: unsupported type")
+	}
+}
@@ -0,0 +1,112 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package mapi
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	type tc struct {
+		v        string
+		unscaled string
+		scale    int
+		e        string
+	}
+	var tcs = []tc{
+		{"6.4", "64", 1, "6.4"},
+		{"-123.4500", "-1234500", 4, "-123.4500"},
+		{"+1", "1", 0, "1"},
+		{"0.0001", "1", 4, "0.0001"},
+		{"-0.50", "-50", 2, "-0.50"},
+		{".5", "5", 1, "0.5"},
+		{"12.", "12", 0, "12"},
+		{"9007199254740993.12345678", "900719925474099312345678", 8, "9007199254740993.12345678"},
+	}
+
+	for _, c := range tcs {
+		d, err := ParseDecimal(c.v)
+		if err != nil {
+			t.Errorf("Error parsing decimal: %s -> %v", c.v, err)
+			continue
+		}
+		if d.Unscaled().String() != c.unscaled || d.Scale() != c.scale {
+			t.Errorf("Invalid value: %s scale %d, expected: %s scale %d", d.Unscaled(), d.Scale(), c.unscaled, c.scale)
+		}
+		if d.String() != c.e {
+			t.Errorf("Invalid value: %s, expected: %s", d.String(), c.e)
+		}
+	}
+
+	for _, v := range []string{"", "-", "1.2.3", "1e5", "--1", "abc", "1,5"} {
+		if _, err := ParseDecimal(v); err == nil {
+			t.Errorf("Invalid decimal %q did not fail", v)
+		}
+	}
+}
+
+func TestDecimal(t *testing.T) {
+	t.Run("Verify the zero value", func(t *testing.T) {
+		var d Decimal
+		if d.String() != "0" || d.Float64() != 0 {
+			t.Errorf("Unexpected zero value %s", d)
+		}
+	})
+
+	t.Run("Verify NewDecimal copies the value", func(t *testing.T) {
+		u := big.NewInt(12345)
+		d := NewDecimal(u, 2)
+		u.SetInt64(1)
+		if d.String() != "123.45" || d.Float64() != 123.45 {
+			t.Errorf("Unexpected value %s", d)
+		}
+	})
+
+	t.Run("Verify Scan", func(t *testing.T) {
+		type tc struct {
+			src any
+			e   string
+		}
+		var tcs = []tc{
+			{[]byte("123.4500"), "123.4500"},
+			{"-1.5", "-1.5"},
+			{int64(42), "42"},
+			{float64(0.25), "0.25"},
+		}
+		for _, c := range tcs {
+			var d Decimal
+			if err := d.Scan(c.src); err != nil {
+				t.Errorf("Error scanning %v -> %v", c.src, err)
+			} else if d.String() != c.e {
+				t.Errorf("Invalid value: %s, expected: %s", d, c.e)
+			}
+		}
+		var d Decimal
+		if err := d.Scan(nil); err == nil {
+			t.Error("Scanning NULL did not fail")
+		}
+	})
+
+	t.Run("Verify conversion of decimal values", func(t *testing.T) {
+		v, err := convertToGo("18446744073709551615.9999", MDB_DECIMAL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d, ok := v.(Decimal)
+		if !ok || d.String() != "18446744073709551615.9999" {
+			t.Errorf("Invalid value: %v", v)
+		}
+
+		s, err := ConvertToMonet(d)
+		if err != nil || s != "18446744073709551615.9999" {
+			t.Errorf("Invalid value: %s (%v)", s, err)
+		}
+		s, err = ConvertToMonet(&d)
+		if err != nil || s != "18446744073709551615.9999" {
+			t.Errorf("Invalid value: %s (%v)", s, err)
+		}
+	})
+}
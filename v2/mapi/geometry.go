@@ -0,0 +1,258 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package mapi
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Geometry is the value of a geometry, geometrya or mbr column of the
+// MonetDB GIS module. The shape is kept as well-known text (WKT), for
+// example "POINT (1 2)". An mbr value is kept as "BOX (x1 y1, x2 y2)".
+type Geometry struct {
+	// SRID is the spatial reference system of the shape, 0 when unknown
+	SRID int
+	WKT  string
+}
+
+// ParseGeometry parses the well-known text of a shape. The text can start
+// with the spatial reference system, for example "SRID=4326;POINT (1 2)".
+func ParseGeometry(s string) (Geometry, error) {
+	var g Geometry
+	s = strings.TrimSpace(s)
+	if len(s) > 5 && strings.EqualFold(s[:5], "SRID=") {
+		i := strings.IndexByte(s, ';')
+		if i < 0 {
+			return g, fmt.Errorf("mapi: invalid geometry %q", s)
+		}
+		srid, err := strconv.Atoi(s[5:i])
+		if err != nil {
+			return g, fmt.Errorf("mapi: invalid geometry %q", s)
+		}
+		g.SRID = srid
+		s = strings.TrimSpace(s[i+1:])
+	}
+	if s == "" {
+		return g, fmt.Errorf("mapi: invalid geometry %q", s)
+	}
+	g.WKT = s
+	return g, nil
+}
+
+// isBox reports whether the geometry is the value of an mbr column.
+func (g Geometry) isBox() bool {
+	return len(g.WKT) >= 3 && strings.EqualFold(g.WKT[:3], "BOX")
+}
+
+// String returns the well-known text of the shape, preceded by the spatial
+// reference system when it is known.
+func (g Geometry) String() string {
+	if g.SRID != 0 {
+		return fmt.Sprintf("SRID=%d;%s", g.SRID, g.WKT)
+	}
+	return g.WKT
+}
+
+// Scan implements the sql.Scanner interface. Text is parsed as well-known
+// text, other bytes are decoded as well-known binary (WKB).
+func (g *Geometry) Scan(src any) error {
+	var err error
+	switch v := src.(type) {
+	case Geometry:
+		*g = v
+	case string:
+		*g, err = ParseGeometry(v)
+	case []byte:
+		if len(v) > 0 && (v[0] == 0 || v[0] == 1) {
+			*g, err = ParseWKB(v)
+		} else {
+			*g, err = ParseGeometry(string(v))
+		}
+	case nil:
+		err = fmt.Errorf("mapi: cannot scan NULL into Geometry")
+	default:
+		err = fmt.Errorf("mapi: cannot scan %T into Geometry", src)
+	}
+	return err
+}
+
+// Value implements the driver.Valuer interface.
+func (g Geometry) Value() (driver.Value, error) {
+	return g.String(), nil
+}
+
+func toGeometry(v string) (Value, error) {
+	s, err := unquoteField(v)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGeometry(s)
+}
+
+func toGeometryString(v Value) (string, error) {
+	switch val := v.(type) {
+	case Geometry:
+		if val.isBox() {
+			box, err := toQuotedString(val.WKT)
+			if err != nil {
+				return "", err
+			}
+			return "mbr " + box, nil
+		}
+		// The server reads the SRID that precedes the well-known text
+		wkt, err := toQuotedString(val.String())
+		if err != nil {
+			return "", err
+		}
+		return "geometry " + wkt, nil
+	default:
+		return "", fmt.Errorf("mapi: unsupported type")
+	}
+}
+
+// The types of shapes that ParseWKB supports
+const (
+	wkbPoint      = 1
+	wkbLineString = 2
+	wkbPolygon    = 3
+	// ewkbSRID is set in the type of extended WKB that contains a SRID
+	ewkbSRID = 0x20000000
+)
+
+// ParseWKB decodes the well-known binary of a point, line string or
+// polygon with two dimensions. The extended format of PostGIS, that
+// contains the SRID, is also accepted.
+func ParseWKB(b []byte) (Geometry, error) {
+	var g Geometry
+	r := wkbReader{b: b}
+	if err := r.byteOrder(); err != nil {
+		return g, err
+	}
+	t := r.uint32()
+	if t&ewkbSRID != 0 {
+		g.SRID = int(r.uint32())
+		t &^= ewkbSRID
+	}
+
+	var sb strings.Builder
+	switch t {
+	case wkbPoint:
+		sb.WriteString("POINT (")
+		r.point(&sb)
+		sb.WriteString(")")
+	case wkbLineString:
+		sb.WriteString("LINESTRING ")
+		r.points(&sb)
+	case wkbPolygon:
+		sb.WriteString("POLYGON ")
+		n := r.uint32()
+		if n == 0 {
+			sb.WriteString("EMPTY")
+			break
+		}
+		sb.WriteString("(")
+		for i := uint32(0); i < n && r.err == nil; i++ {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			r.points(&sb)
+		}
+		sb.WriteString(")")
+	default:
+		return g, fmt.Errorf("mapi: unsupported wkb geometry type %d", t)
+	}
+	if r.err != nil {
+		return g, r.err
+	}
+	if len(r.b) != 0 {
+		return g, fmt.Errorf("mapi: invalid wkb, %d bytes left", len(r.b))
+	}
+	g.WKT = sb.String()
+	return g, nil
+}
+
+// wkbReader reads the values of well-known binary. After an error the
+// values are zero and the error is kept.
+type wkbReader struct {
+	b     []byte
+	order binary.ByteOrder
+	err   error
+}
+
+func (r *wkbReader) byteOrder() error {
+	if len(r.b) == 0 {
+		return fmt.Errorf("mapi: invalid wkb, no data")
+	}
+	switch r.b[0] {
+	case 0:
+		r.order = binary.BigEndian
+	case 1:
+		r.order = binary.LittleEndian
+	default:
+		return fmt.Errorf("mapi: invalid wkb byte order %d", r.b[0])
+	}
+	r.b = r.b[1:]
+	return nil
+}
+
+func (r *wkbReader) uint32() uint32 {
+	if r.err != nil {
+		return 0
+	}
+	if len(r.b) < 4 {
+		r.err = fmt.Errorf("mapi: invalid wkb, unexpected end of data")
+		return 0
+	}
+	v := r.order.Uint32(r.b)
+	r.b = r.b[4:]
+	return v
+}
+
+func (r *wkbReader) float64() float64 {
+	if r.err != nil {
+		return 0
+	}
+	if len(r.b) < 8 {
+		r.err = fmt.Errorf("mapi: invalid wkb, unexpected end of data")
+		return 0
+	}
+	v := math.Float64frombits(r.order.Uint64(r.b))
+	r.b = r.b[8:]
+	return v
+}
+
+// point writes the coordinates of a point in the form "x y".
+func (r *wkbReader) point(sb *strings.Builder) {
+	x, y := r.float64(), r.float64()
+	sb.WriteString(strconv.FormatFloat(x, 'f', -1, 64))
+	sb.WriteString(" ")
+	sb.WriteString(strconv.FormatFloat(y, 'f', -1, 64))
+}
+
+// points writes a list of points in the form "(x1 y1, x2 y2)", or EMPTY
+// when there are no points.
+func (r *wkbReader) points(sb *strings.Builder) {
+	n := r.uint32()
+	if r.err == nil && uint64(n)*16 > uint64(len(r.b)) {
+		r.err = fmt.Errorf("mapi: invalid wkb, unexpected end of data")
+	}
+	if n == 0 {
+		sb.WriteString("EMPTY")
+		return
+	}
+	sb.WriteString("(")
+	for i := uint32(0); i < n && r.err == nil; i++ {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		r.point(sb)
+	}
+	sb.WriteString(")")
+}
@@ -0,0 +1,101 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package mapi
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+	"testing"
+)
+
+func TestGeometry(t *testing.T) {
+	type tc struct {
+		v string
+		t string
+		e Geometry
+		s string
+	}
+	var tcs = []tc{
+		{`"POINT (1 2)"`, "geometry", Geometry{WKT: "POINT (1 2)"}, "geometry 'POINT (1 2)'"},
+		{`"SRID=4326;LINESTRING (0 0, 1.5 -2)"`, "geometry", Geometry{SRID: 4326, WKT: "LINESTRING (0 0, 1.5 -2)"},
+			"geometry 'SRID=4326;LINESTRING (0 0, 1.5 -2)'"},
+		{"BOX (0 0, 3 4)", "mbr", Geometry{WKT: "BOX (0 0, 3 4)"}, "mbr 'BOX (0 0, 3 4)'"},
+	}
+
+	for _, c := range tcs {
+		v, err := convertToGo(c.v, c.t)
+		if err != nil {
+			t.Errorf("Error converting value: %v (%s) -> %v", c.v, c.t, err)
+			continue
+		}
+		if v != c.e {
+			t.Errorf("Invalid value: %v (%v - %s), expected: %v", v, c.v, c.t, c.e)
+		}
+		s, err := ConvertToMonet(v)
+		if err != nil || s != c.s {
+			t.Errorf("Invalid value: %s, expected: %s", s, c.s)
+		}
+	}
+
+	if _, err := ParseGeometry("SRID=x;POINT (1 2)"); err == nil {
+		t.Error("Invalid SRID did not fail")
+	}
+}
+
+func TestParseWKB(t *testing.T) {
+	type tc struct {
+		wkb string
+		e   Geometry
+	}
+	var tcs = []tc{
+		{"0101000000000000000000f03f0000000000000040", Geometry{WKT: "POINT (1 2)"}},
+		{"000000000140080000000000003ff8000000000000", Geometry{WKT: "POINT (3 1.5)"}},
+		{"0102000000020000000000000000000000000000000000000000000000000024400000000000002440",
+			Geometry{WKT: "LINESTRING (0 0, 10 10)"}},
+		{"010300000000000000", Geometry{WKT: "POLYGON EMPTY"}},
+		{"0102000020e610000000000000", Geometry{SRID: 4326, WKT: "LINESTRING EMPTY"}},
+	}
+
+	for _, c := range tcs {
+		b, err := hex.DecodeString(c.wkb)
+		if err != nil {
+			t.Fatal(err)
+		}
+		g, err := ParseWKB(b)
+		if err != nil {
+			t.Errorf("Error decoding wkb: %s -> %v", c.wkb, err)
+		} else if g != c.e {
+			t.Errorf("Invalid value: %v, expected: %v", g, c.e)
+		}
+	}
+
+	// A polygon with a square ring
+	b := []byte{1}
+	for _, n := range []uint32{3, 1, 5} {
+		b = append(b, 0, 0, 0, 0)
+		binary.LittleEndian.PutUint32(b[len(b)-4:], n)
+	}
+	for _, p := range [][2]float64{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}} {
+		for _, f := range p {
+			b = append(b, 0, 0, 0, 0, 0, 0, 0, 0)
+			binary.LittleEndian.PutUint64(b[len(b)-8:], math.Float64bits(f))
+		}
+	}
+	var g Geometry
+	if err := g.Scan(b); err != nil {
+		t.Fatal(err)
+	}
+	if g.WKT != "POLYGON ((0 0, 4 0, 4 4, 0 4, 0 0))" {
+		t.Errorf("Invalid value: %v", g)
+	}
+
+	if _, err := ParseWKB(b[:len(b)-1]); err == nil {
+		t.Error("Truncated wkb did not fail")
+	}
+	if _, err := ParseWKB([]byte{1, 4, 0, 0, 0}); err == nil {
+		t.Error("Unsupported wkb type did not fail")
+	}
+}
@@ -0,0 +1,114 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package mapi
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MonthInterval is the value of a month_interval column, for example
+// INTERVAL '1' YEAR, as a number of months.
+type MonthInterval int32
+
+// String returns the interval in the form "14 months".
+func (m MonthInterval) String() string {
+	return fmt.Sprintf("%d months", int32(m))
+}
+
+// Scan implements the sql.Scanner interface.
+func (m *MonthInterval) Scan(src any) error {
+	var err error
+	switch v := src.(type) {
+	case MonthInterval:
+		*m = v
+	case int64:
+		*m = MonthInterval(v)
+	case string:
+		err = m.parse(v)
+	case []byte:
+		err = m.parse(string(v))
+	case nil:
+		err = fmt.Errorf("mapi: cannot scan NULL into MonthInterval")
+	default:
+		err = fmt.Errorf("mapi: cannot scan %T into MonthInterval", src)
+	}
+	return err
+}
+
+func (m *MonthInterval) parse(s string) error {
+	i, err := strconv.ParseInt(s, 10, 32)
+	if err != nil {
+		return fmt.Errorf("mapi: invalid month interval %q", s)
+	}
+	*m = MonthInterval(i)
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (m MonthInterval) Value() (driver.Value, error) {
+	return int64(m), nil
+}
+
+// toMonthInterval decodes a month_interval value, which the server sends
+// as the number of months.
+func toMonthInterval(v string) (Value, error) {
+	var m MonthInterval
+	if err := m.parse(v); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func toMonthIntervalString(v Value) (string, error) {
+	switch val := v.(type) {
+	case MonthInterval:
+		return fmt.Sprintf("INTERVAL '%d' MONTH", int32(val)), nil
+	default:
+		return "", fmt.Errorf("mapi: unsupported type")
+	}
+}
+
+// toDuration decodes a sec_interval or day_interval value, which the server
+// sends as a number of seconds with the milliseconds, for example
+// "3600.000".
+func toDuration(v string) (Value, error) {
+	d, err := ParseDecimal(v)
+	if err != nil || d.scale > 9 {
+		return nil, fmt.Errorf("mapi: invalid interval %q", v)
+	}
+	nsec := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(9-d.scale)), nil)
+	nsec.Mul(nsec, d.unscaled)
+	if !nsec.IsInt64() {
+		return nil, fmt.Errorf("mapi: interval %q out of range", v)
+	}
+	return time.Duration(nsec.Int64()), nil
+}
+
+// toDurationString converts a time.Duration to an interval of seconds. The
+// fraction of the second is written without trailing zeros.
+func toDurationString(v Value) (string, error) {
+	switch val := v.(type) {
+	case time.Duration:
+		sign := ""
+		// The absolute value of the minimum duration does not fit in
+		// an int64, so the seconds and the fraction are negated separately
+		sec, nsec := int64(val/time.Second), int64(val%time.Second)
+		if val < 0 {
+			sign, sec, nsec = "-", -sec, -nsec
+		}
+		s := fmt.Sprintf("%s%d", sign, sec)
+		if nsec != 0 {
+			s += strings.TrimRight(fmt.Sprintf(".%09d", nsec), "0")
+		}
+		return fmt.Sprintf("INTERVAL '%s' SECOND", s), nil
+	default:
+		return "", fmt.Errorf("mapi: unsupported type")
+	}
+}
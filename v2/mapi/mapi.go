@@ -17,6 +17,8 @@ import (
 	"net"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 const (
@@ -75,9 +77,30 @@ type MapiConn struct {
 	replySize  int
 	autoCommit bool
 
+	// mu protects the conn field against Interrupt, which can be called
+	// from another goroutine
+	mu   sync.Mutex
 	conn *net.TCPConn
 }
 
+// ConnError is returned when the communication with the server fails. Sent
+// reports whether the command could have reached the server. When the
+// command was not sent, it is safe to retry it on another connection.
+//
+// After a ConnError the connection is closed.
+type ConnError struct {
+	Err  error
+	Sent bool
+}
+
+func (e *ConnError) Error() string {
+	return fmt.Sprintf("mapi: connection failed: %v", e.Err)
+}
+
+func (e *ConnError) Unwrap() error {
+	return e.Err
+}
+
 // NewMapi returns a MonetDB's MAPI connection handle.
 //
 // To establish the connection, call the Connect() function.
@@ -100,12 +123,14 @@ func NewMapi(name string) (*MapiConn, error) {
 
 		sizeHeader: true,
 		replySize : MAPI_ARRAY_SIZE,
-		autoCommit: true,
+		autoCommit: c.AutoCommit,
 	}, nil
 }
 
 // Disconnect closes the connection.
 func (c *MapiConn) Disconnect() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.State = mapi_STATE_INIT
 	if c.conn != nil {
 		c.conn.Close()
@@ -113,14 +138,29 @@ func (c *MapiConn) Disconnect() {
 	}
 }
 
-func (c *MapiConn) Execute(query string) (string, error) {
+// Interrupt aborts a command that is waiting for the server. It can be
+// called from another goroutine. The command fails with a ConnError and the
+// connection is closed, because the state of the session is unknown.
+func (c *MapiConn) Interrupt() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn != nil {
+		c.conn.SetDeadline(time.Now())
+	}
+}
+
+// Execute sends the query to the server and returns the parsed reply. When
+// the server reports an error, the response is returned together with the
+// first *Error part of the response.
+func (c *MapiConn) Execute(query string) (*Response, error) {
 	cmd := fmt.Sprintf("s%s;", query)
-	return c.cmd(cmd)
+	return c.request(cmd)
 }
 
-func (c *MapiConn) FetchNext(queryId int, offset int, amount int) (string, error) {
+// FetchNext retrieves the next block of rows of an open result set.
+func (c *MapiConn) FetchNext(queryId int, offset int, amount int) (*Response, error) {
 	cmd := fmt.Sprintf("Xexport %d %d %d", queryId, offset, amount)
-	return c.cmd(cmd)
+	return c.request(cmd)
 }
 
 func (c *MapiConn) SetSizeHeader(enable bool) (string, error) {
@@ -144,46 +184,97 @@ func (c *MapiConn) SetAutoCommit(enable bool) (string, error) {
 		autoCommit = 1
 	}
 	cmd := fmt.Sprintf("Xauto_commit %d", autoCommit)
+	r, err := c.cmd(cmd)
+	if err == nil {
+		c.autoCommit = enable
+	}
+	return r, err
+}
+
+// AutoCommit reports whether autocommit is enabled for the session.
+func (c *MapiConn) AutoCommit() bool {
+	return c.autoCommit
+}
+
+// CloseResult releases an open result set on the server.
+func (c *MapiConn) CloseResult(queryId int) (string, error) {
+	cmd := fmt.Sprintf("Xclose %d", queryId)
 	return c.cmd(cmd)
 }
 
-// Cmd sends a MAPI command to MonetDB.
-func (c *MapiConn) cmd(operation string) (string, error) {
-	if c.State != mapi_STATE_READY {
-		return "", fmt.Errorf("mapi: database is not connected")
+// ReleasePrepared releases a prepared statement on the server.
+func (c *MapiConn) ReleasePrepared(execId int) (string, error) {
+	cmd := fmt.Sprintf("Xrelease %d", execId)
+	return c.cmd(cmd)
+}
+
+// request sends a MAPI command to MonetDB and parses the reply.
+func (c *MapiConn) request(operation string) (*Response, error) {
+	r, err := c.exchange(operation)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := c.putBlock([]byte(operation)); err != nil {
-		return "", err
+	resp, err := ParseResponse(r)
+	if err != nil {
+		return nil, err
 	}
+	return resp, resp.Err()
+}
 
-	r, err := c.getBlock()
+// Cmd sends a MAPI command to MonetDB.
+func (c *MapiConn) cmd(operation string) (string, error) {
+	resp, err := c.exchange(operation)
 	if err != nil {
 		return "", err
 	}
 
-	resp := string(r)
 	if len(resp) == 0 {
 		return "", nil
 
 	} else if strings.HasPrefix(resp, mapi_MSG_OK) {
 		return strings.TrimSpace(resp[3:]), nil
 
-	} else if resp == mapi_MSG_MORE {
-		// tell server it isn't going to get more
-		return c.cmd("")
-
 	} else if strings.HasPrefix(resp, mapi_MSG_Q) || strings.HasPrefix(resp, mapi_MSG_HEADER) || strings.HasPrefix(resp, mapi_MSG_TUPLE) {
 		return resp, nil
 
 	} else if strings.HasPrefix(resp, mapi_MSG_ERROR) {
-		return "", fmt.Errorf("mapi: operational error: %s", resp[1:])
+		r, err := ParseResponse(resp)
+		if err != nil {
+			return "", err
+		}
+		return "", r.Err()
 
 	} else {
 		return "", fmt.Errorf("mapi: unknown state: %s", resp)
 	}
 }
 
+// exchange sends a MAPI command to MonetDB and returns the raw reply.
+func (c *MapiConn) exchange(operation string) (string, error) {
+	if c.State != mapi_STATE_READY {
+		return "", fmt.Errorf("mapi: database is not connected")
+	}
+
+	if err := c.putBlock([]byte(operation)); err != nil {
+		c.Disconnect()
+		return "", &ConnError{Err: err, Sent: false}
+	}
+
+	r, err := c.getBlock()
+	if err != nil {
+		c.Disconnect()
+		return "", &ConnError{Err: err, Sent: true}
+	}
+
+	resp := string(r)
+	if resp == mapi_MSG_MORE {
+		// tell server it isn't going to get more
+		return c.exchange("")
+	}
+	return resp, nil
+}
+
 // Connect starts a MAPI connection to MonetDB server.
 func (c *MapiConn) Connect() error {
 	if c.conn != nil {
@@ -211,6 +302,13 @@ func (c *MapiConn) Connect() error {
 		return err
 	}
 
+	// A new session always starts with autocommit enabled
+	if !c.autoCommit {
+		if _, err := c.SetAutoCommit(false); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,97 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package mapi
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// newTestConn returns a MapiConn that is connected to a local listener. The
+// server side of the connection is returned, so the test can close it.
+func newTestConn(t *testing.T) (*MapiConn, net.Conn) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		s, err := l.Accept()
+		if err != nil {
+			accepted <- nil
+			return
+		}
+		accepted <- s
+	}()
+
+	raddr := l.Addr().(*net.TCPAddr)
+	conn, err := net.DialTCP("tcp", nil, raddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := <-accepted
+	if server == nil {
+		t.Fatal("could not accept connection")
+	}
+
+	c := &MapiConn{State: mapi_STATE_READY, conn: conn}
+	t.Cleanup(func() {
+		c.Disconnect()
+		server.Close()
+	})
+	return c, server
+}
+
+func TestConnError(t *testing.T) {
+	t.Run("Verify ConnError when the server closes the connection", func(t *testing.T) {
+		c, server := newTestConn(t)
+		server.Close()
+
+		_, err := c.Execute("select 1")
+		var e *ConnError
+		if !errors.As(err, &e) {
+			t.Fatalf("Unexpected error %v", err)
+		}
+		if c.State != mapi_STATE_INIT {
+			t.Error("Connection was not closed after failure")
+		}
+	})
+
+	t.Run("Verify Interrupt aborts a command", func(t *testing.T) {
+		c, _ := newTestConn(t)
+
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			c.Interrupt()
+		}()
+		// The server never answers
+		_, err := c.Execute("select 1")
+		var e *ConnError
+		if !errors.As(err, &e) {
+			t.Fatalf("Unexpected error %v", err)
+		}
+		if !e.Sent {
+			t.Error("ConnError reports that the command was not sent")
+		}
+	})
+
+	t.Run("Verify ConnError when the command could not be sent", func(t *testing.T) {
+		c, _ := newTestConn(t)
+		c.conn.CloseWrite()
+
+		_, err := c.Execute("select 1")
+		var e *ConnError
+		if !errors.As(err, &e) {
+			t.Fatalf("Unexpected error %v", err)
+		}
+		if e.Sent {
+			t.Error("ConnError reports that the command was sent")
+		}
+	})
+}
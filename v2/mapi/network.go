@@ -0,0 +1,103 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package mapi
+
+import (
+	"fmt"
+	"net/netip"
+	"net/url"
+	"strings"
+)
+
+// unquoteField removes the quotes of a value that the server sends as a
+// quoted string.
+func unquoteField(v string) (string, error) {
+	if len(v) >= 2 && v[0] == '"' && v[len(v)-1] == '"' {
+		s, err := strip(v)
+		if err != nil {
+			return "", err
+		}
+		return s.(string), nil
+	}
+	return v, nil
+}
+
+// toInet decodes an inet value. The value is returned as a netip.Prefix, so
+// the prefix length is kept. The server leaves out the prefix length of a
+// single address, the prefix of such an address contains all its bits.
+func toInet(v string) (Value, error) {
+	s, err := unquoteField(v)
+	if err != nil {
+		return nil, err
+	}
+	if strings.Contains(s, "/") {
+		p, err := netip.ParsePrefix(s)
+		if err != nil {
+			return nil, fmt.Errorf("mapi: invalid inet value %q", v)
+		}
+		return p, nil
+	}
+	a, err := netip.ParseAddr(s)
+	if err != nil {
+		return nil, fmt.Errorf("mapi: invalid inet value %q", v)
+	}
+	return netip.PrefixFrom(a, a.BitLen()), nil
+}
+
+func toInetString(v Value) (string, error) {
+	var s string
+	switch val := v.(type) {
+	case netip.Addr:
+		if !val.IsValid() {
+			return "", fmt.Errorf("mapi: invalid inet value")
+		}
+		s = val.String()
+	case netip.Prefix:
+		if !val.IsValid() {
+			return "", fmt.Errorf("mapi: invalid inet value")
+		}
+		s = val.String()
+	default:
+		return "", fmt.Errorf("mapi: unsupported type")
+	}
+	q, err := toQuotedString(s)
+	if err != nil {
+		return "", err
+	}
+	return "inet " + q, nil
+}
+
+// toURL decodes a url value into a *url.URL.
+func toURL(v string) (Value, error) {
+	s, err := unquoteField(v)
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("mapi: invalid url value %q", v)
+	}
+	return u, nil
+}
+
+func toURLString(v Value) (string, error) {
+	var s string
+	switch val := v.(type) {
+	case *url.URL:
+		if val == nil {
+			return toNull(nil)
+		}
+		s = val.String()
+	case url.URL:
+		s = val.String()
+	default:
+		return "", fmt.Errorf("mapi: unsupported type")
+	}
+	q, err := toQuotedString(s)
+	if err != nil {
+		return "", err
+	}
+	return "url " + q, nil
+}
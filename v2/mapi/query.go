@@ -14,40 +14,39 @@ type Query struct {
 	SqlQuery string
 }
 
-func (q *Query) execute(query string) (string, error) {
+func (q *Query) execute(query string) (*Response, error) {
 	if q.Mapi == nil {
-		return "", fmt.Errorf("monetdb: database connection is closed")
+		return nil, fmt.Errorf("monetdb: database connection is closed")
 	}
 	return q.Mapi.Execute(query)
 }
 
 func (q *Query) PrepareQuery(r *ResultSet) error {
 	querystring := fmt.Sprintf("PREPARE %s", q.SqlQuery)
-	resultstring, err := q.execute(querystring)
+	resp, err := q.execute(querystring)
 
 	if err != nil {
 		return err
 	}
-	return r.StoreResult(resultstring)
+	return r.StoreResponse(resp)
 }
 
-func (q *Query) ExecutePreparedQuery(r *ResultSet, args []Value) (string, error) {
+func (q *Query) ExecutePreparedQuery(r *ResultSet, args []Value) (*Response, error) {
 	execStr, err := r.CreateExecString(args)
 	if err != nil {
-		return "", err
+		return nil, err
 	} 
 	return q.execute(execStr)
 }
 
-func (q *Query) ExecuteNamedQuery(r *ResultSet, names []string, args []Value) (string, error) {
+func (q *Query) ExecuteNamedQuery(r *ResultSet, names []string, args []Value) (*Response, error) {
 	execStr, err := r.CreateNamedString(q.SqlQuery, names, args)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	return q.execute(execStr)
 }
 
-func (q *Query) ExecuteQuery(r *ResultSet) (string, error) {
+func (q *Query) ExecuteQuery(r *ResultSet) (*Response, error) {
 	return q.execute(q.SqlQuery)
 }
-
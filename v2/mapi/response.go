@@ -0,0 +1,377 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package mapi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Response is the parsed reply of the server to a single MAPI command.
+//
+// One command can result in several parts, for example when the query
+// contains more than one statement. The parts are stored in the order
+// in which the server sent them.
+type Response struct {
+	Parts []ResponsePart
+}
+
+// ResponsePart is one part of a Response. It is one of *ResultTable,
+// *ResultBlock, *UpdateCount, *SchemaChange, *TransactionChange,
+// *PrepareResult, *Info or *Error.
+type ResponsePart interface {
+	responsePart()
+}
+
+// ResultTable is the first block of a query result, the "&1" response.
+// The Tuples contain the unconverted rows of this block. When TupleCount
+// is smaller than RowCount, the remaining rows can be retrieved with
+// MapiConn.FetchNext.
+type ResultTable struct {
+	QueryId     int
+	RowCount    int
+	ColumnCount int
+	TupleCount  int
+	Schema      []TableElement
+	Tuples      []string
+}
+
+// ResultBlock is a subsequent block of a query result, the "&6" response
+// to MapiConn.FetchNext.
+type ResultBlock struct {
+	QueryId     int
+	ColumnCount int
+	TupleCount  int
+	Offset      int
+	Tuples      []string
+}
+
+// UpdateCount is the "&2" response to a statement that changes data.
+type UpdateCount struct {
+	RowCount  int
+	LastRowId int
+}
+
+// SchemaChange is the "&3" response to a statement that changes the
+// schema, for example a CREATE TABLE statement.
+type SchemaChange struct {
+}
+
+// TransactionChange is the "&4" response to a statement that starts or
+// ends a transaction. AutoCommit reports the new autocommit state of the
+// session.
+type TransactionChange struct {
+	AutoCommit bool
+}
+
+// PrepareResult is the "&5" response to a PREPARE statement. The ExecId is
+// used to execute the prepared statement. The Schema and Tuples describe the
+// result columns and the parameters of the statement.
+type PrepareResult struct {
+	ExecId      int
+	RowCount    int
+	ColumnCount int
+	TupleCount  int
+	Schema      []TableElement
+	Tuples      []string
+}
+
+// Info is an informational message from the server, the "#" response.
+type Info struct {
+	Message string
+}
+
+// Error is an error reported by the server, the "!" response. Consecutive
+// error lines are collected in a single Error. The SQLState and Message are
+// taken from the first line, Lines contains all lines as they were sent by
+// the server, without the leading "!".
+//
+// Use errors.As to retrieve the Error from an error that is returned by the
+// driver.
+type Error struct {
+	SQLState string
+	Message  string
+	Lines    []string
+}
+
+func (*ResultTable) responsePart()       {}
+func (*ResultBlock) responsePart()       {}
+func (*UpdateCount) responsePart()       {}
+func (*SchemaChange) responsePart()      {}
+func (*TransactionChange) responsePart() {}
+func (*PrepareResult) responsePart()     {}
+func (*Info) responsePart()              {}
+func (*Error) responsePart()             {}
+
+// newError creates an Error from the first error line of a response. The
+// line starts with a five character SQLSTATE code followed by a "!", but
+// some messages of the server do not include a code.
+func newError(line string) *Error {
+	e := &Error{
+		Message: line,
+		Lines:   []string{line},
+	}
+	if len(line) > 5 && line[5] == '!' && isSQLState(line[:5]) {
+		e.SQLState = line[:5]
+		e.Message = line[6:]
+	}
+	return e
+}
+
+func isSQLState(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !('0' <= c && c <= '9' || 'A' <= c && c <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *Error) Error() string {
+	if len(e.Lines) == 0 {
+		return "mapi: operational error"
+	}
+	return fmt.Sprintf("mapi: operational error: %s", e.Lines[0])
+}
+
+// Err returns the first error part of the response, or nil when the
+// server did not report an error.
+func (r *Response) Err() error {
+	for _, p := range r.Parts {
+		if e, ok := p.(*Error); ok {
+			return e
+		}
+	}
+	return nil
+}
+
+// Tuples returns the unconverted rows of the first result table or result
+// block in the response.
+func (r *Response) Tuples() []string {
+	for _, p := range r.Parts {
+		switch v := p.(type) {
+		case *ResultTable:
+			return v.Tuples
+		case *ResultBlock:
+			return v.Tuples
+		}
+	}
+	return nil
+}
+
+// Table returns the last result table of the response, or nil when the
+// response does not contain a result table. When the command contains more
+// than one query, this is the result of the last query.
+func (r *Response) Table() *ResultTable {
+	var table *ResultTable
+	for _, p := range r.Parts {
+		if t, ok := p.(*ResultTable); ok {
+			table = t
+		}
+	}
+	return table
+}
+
+// ParseResponse parses the raw reply of the server into a Response.
+func ParseResponse(r string) (*Response, error) {
+	resp := &Response{}
+	var header *headerParser
+
+	for _, line := range strings.Split(r, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, mapi_MSG_INFO) {
+			resp.Parts = append(resp.Parts, &Info{Message: strings.TrimSpace(line[1:])})
+
+		} else if strings.HasPrefix(line, mapi_MSG_ERROR) {
+			if n := len(resp.Parts); n > 0 {
+				if e, ok := resp.Parts[n-1].(*Error); ok {
+					e.Lines = append(e.Lines, line[1:])
+					continue
+				}
+			}
+			resp.Parts = append(resp.Parts, newError(line[1:]))
+
+		} else if strings.HasPrefix(line, mapi_MSG_QTABLE) {
+			t, err := parseHeaderFields(line, 4)
+			if err != nil {
+				return nil, err
+			}
+			table := &ResultTable{
+				QueryId:     t[0],
+				RowCount:    t[1],
+				ColumnCount: t[2],
+				TupleCount:  t[3],
+			}
+			header = newHeaderParser(table.ColumnCount, &table.Schema)
+			resp.Parts = append(resp.Parts, table)
+
+		} else if strings.HasPrefix(line, mapi_MSG_QUPDATE) {
+			t, err := parseHeaderFields(line, 2)
+			if err != nil {
+				return nil, err
+			}
+			resp.Parts = append(resp.Parts, &UpdateCount{RowCount: t[0], LastRowId: t[1]})
+			header = nil
+
+		} else if strings.HasPrefix(line, mapi_MSG_QSCHEMA) {
+			resp.Parts = append(resp.Parts, &SchemaChange{})
+			header = nil
+
+		} else if strings.HasPrefix(line, mapi_MSG_QTRANS) {
+			flag := strings.TrimSpace(line[2:])
+			resp.Parts = append(resp.Parts, &TransactionChange{AutoCommit: flag == "t"})
+			header = nil
+
+		} else if strings.HasPrefix(line, mapi_MSG_QPREPARE) {
+			t, err := parseHeaderFields(line, 4)
+			if err != nil {
+				return nil, err
+			}
+			prepare := &PrepareResult{
+				ExecId:      t[0],
+				RowCount:    t[1],
+				ColumnCount: t[2],
+				TupleCount:  t[3],
+			}
+			header = newHeaderParser(prepare.ColumnCount, &prepare.Schema)
+			resp.Parts = append(resp.Parts, prepare)
+
+		} else if strings.HasPrefix(line, mapi_MSG_QBLOCK) {
+			t, err := parseHeaderFields(line, 4)
+			if err != nil {
+				return nil, err
+			}
+			resp.Parts = append(resp.Parts, &ResultBlock{
+				QueryId:     t[0],
+				ColumnCount: t[1],
+				TupleCount:  t[2],
+				Offset:      t[3],
+			})
+			header = nil
+
+		} else if strings.HasPrefix(line, mapi_MSG_HEADER) {
+			if header == nil {
+				return nil, fmt.Errorf("mapi: unexpected header: %s", line)
+			}
+			header.parse(line)
+
+		} else if strings.HasPrefix(line, mapi_MSG_TUPLE) {
+			if err := resp.appendTuple(line); err != nil {
+				return nil, err
+			}
+
+		} else if strings.HasPrefix(line, mapi_MSG_OK) {
+			// pass
+
+		} else {
+			return nil, fmt.Errorf("mapi: unknown state: %s", line)
+		}
+	}
+
+	return resp, nil
+}
+
+func (r *Response) appendTuple(line string) error {
+	if n := len(r.Parts); n > 0 {
+		switch p := r.Parts[n-1].(type) {
+		case *ResultTable:
+			p.Tuples = append(p.Tuples, line)
+			return nil
+		case *ResultBlock:
+			p.Tuples = append(p.Tuples, line)
+			return nil
+		case *PrepareResult:
+			p.Tuples = append(p.Tuples, line)
+			return nil
+		}
+	}
+	return fmt.Errorf("mapi: unexpected tuple: %s", line)
+}
+
+// parseHeaderFields returns the numeric fields of a "&" response line. At
+// least count fields must be present, additional fields are ignored.
+func parseHeaderFields(line string, count int) ([]int, error) {
+	t := strings.Fields(line[2:])
+	if len(t) < count {
+		return nil, fmt.Errorf("mapi: invalid response header: %s", line)
+	}
+	res := make([]int, count)
+	for i := 0; i < count; i++ {
+		v, err := strconv.Atoi(t[i])
+		if err != nil {
+			return nil, fmt.Errorf("mapi: invalid response header: %s", line)
+		}
+		res[i] = v
+	}
+	return res, nil
+}
+
+// headerParser collects the "%" lines that follow a result table or
+// prepare response into the schema of that response.
+type headerParser struct {
+	schema *[]TableElement
+}
+
+func newHeaderParser(columnCount int, schema *[]TableElement) *headerParser {
+	*schema = make([]TableElement, columnCount)
+	return &headerParser{schema: schema}
+}
+
+func (h *headerParser) parse(line string) {
+	t := strings.Split(line[1:], "#")
+	if len(t) < 2 {
+		return
+	}
+	data := strings.TrimSpace(t[0])
+	identity := strings.TrimSpace(t[1])
+
+	values := make([]string, 0)
+	for _, value := range strings.Split(data, ",") {
+		values = append(values, strings.TrimSpace(value))
+	}
+
+	schema := *h.schema
+	if len(values) != len(schema) {
+		return
+	}
+
+	switch identity {
+	case "name":
+		for i, value := range values {
+			schema[i].ColumnName = value
+		}
+	case "type":
+		for i, value := range values {
+			schema[i].ColumnType = value
+		}
+	case "typesizes":
+		for i, value := range values {
+			sizes := parseSizes(value)
+			schema[i].InternalSize = sizes[0]
+			if schema[i].ColumnType == MDB_DECIMAL && len(sizes) > 1 {
+				schema[i].Precision = sizes[0]
+				schema[i].Scale = sizes[1]
+			}
+		}
+	case "length":
+		for i, value := range values {
+			schema[i].DisplaySize = parseSizes(value)[0]
+		}
+	}
+}
+
+func parseSizes(value string) []int {
+	s := make([]int, 0)
+	for _, v := range strings.Split(value, " ") {
+		val, _ := strconv.Atoi(v)
+		s = append(s, val)
+	}
+	return s
+}
@@ -0,0 +1,155 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package mapi
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseResponse(t *testing.T) {
+	t.Run("Verify ParseResponse with empty response", func(t *testing.T) {
+		resp, err := ParseResponse("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Parts) != 0 {
+			t.Errorf("Unexpected number of parts %d", len(resp.Parts))
+		}
+	})
+
+	t.Run("Verify ParseResponse with result table", func(t *testing.T) {
+		var response = `&1 2 3 2 2 0 201 169 7
+% sys.test1,	sys.test1 # table_name
+% id,	name # name
+% int,	varchar # type
+% 1,	5 # length
+% 32 0,	16 0 # typesizes
+[ 1,	"name1"	]
+[ 2,	"name2"	]
+`
+		resp, err := ParseResponse(response)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Parts) != 1 {
+			t.Fatalf("Unexpected number of parts %d", len(resp.Parts))
+		}
+		table, ok := resp.Parts[0].(*ResultTable)
+		if !ok {
+			t.Fatalf("Unexpected part %T", resp.Parts[0])
+		}
+		if table.QueryId != 2 || table.RowCount != 3 || table.ColumnCount != 2 || table.TupleCount != 2 {
+			t.Errorf("Unexpected header %+v", table)
+		}
+		if len(table.Tuples) != 2 {
+			t.Errorf("Unexpected number of tuples %d", len(table.Tuples))
+		}
+		if table.Schema[1].ColumnName != "name" || table.Schema[1].ColumnType != "varchar" {
+			t.Errorf("Unexpected schema %+v", table.Schema[1])
+		}
+		if table.Schema[1].DisplaySize != 5 || table.Schema[1].InternalSize != 16 {
+			t.Errorf("Unexpected sizes %+v", table.Schema[1])
+		}
+	})
+
+	t.Run("Verify ParseResponse with result block", func(t *testing.T) {
+		resp, err := ParseResponse("&6 2 1 1 100\n[ \"name101\"\t]\n")
+		if err != nil {
+			t.Fatal(err)
+		}
+		block, ok := resp.Parts[0].(*ResultBlock)
+		if !ok {
+			t.Fatalf("Unexpected part %T", resp.Parts[0])
+		}
+		if block.QueryId != 2 || block.Offset != 100 || len(block.Tuples) != 1 {
+			t.Errorf("Unexpected block %+v", block)
+		}
+	})
+
+	t.Run("Verify ParseResponse with multiple statements", func(t *testing.T) {
+		resp, err := ParseResponse("&4 f\n&2 1 -1\n&3 12 34\n&4 t\n")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Parts) != 4 {
+			t.Fatalf("Unexpected number of parts %d", len(resp.Parts))
+		}
+		if tc, ok := resp.Parts[0].(*TransactionChange); !ok || tc.AutoCommit {
+			t.Errorf("Unexpected part %+v", resp.Parts[0])
+		}
+		if uc, ok := resp.Parts[1].(*UpdateCount); !ok || uc.RowCount != 1 || uc.LastRowId != -1 {
+			t.Errorf("Unexpected part %+v", resp.Parts[1])
+		}
+		if _, ok := resp.Parts[2].(*SchemaChange); !ok {
+			t.Errorf("Unexpected part %+v", resp.Parts[2])
+		}
+		if tc, ok := resp.Parts[3].(*TransactionChange); !ok || !tc.AutoCommit {
+			t.Errorf("Unexpected part %+v", resp.Parts[3])
+		}
+	})
+
+	t.Run("Verify ParseResponse with error", func(t *testing.T) {
+		resp, err := ParseResponse("#info\n!42S02!SELECT: no such table 'x'\n!second line\n")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Parts) != 2 {
+			t.Fatalf("Unexpected number of parts %d", len(resp.Parts))
+		}
+		var e *Error
+		if !errors.As(resp.Err(), &e) {
+			t.Fatalf("Unexpected error %v", resp.Err())
+		}
+		if len(e.Lines) != 2 {
+			t.Errorf("Unexpected number of error lines %d", len(e.Lines))
+		}
+		if e.SQLState != "42S02" {
+			t.Errorf("Unexpected SQLSTATE %s", e.SQLState)
+		}
+		if e.Message != "SELECT: no such table 'x'" {
+			t.Errorf("Unexpected message %s", e.Message)
+		}
+		if e.Error() != "mapi: operational error: 42S02!SELECT: no such table 'x'" {
+			t.Errorf("Unexpected error string %s", e.Error())
+		}
+	})
+
+	t.Run("Verify ParseResponse with error without SQLSTATE", func(t *testing.T) {
+		resp, err := ParseResponse("!SELECT: no such table 'x'\n")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var e *Error
+		if !errors.As(resp.Err(), &e) {
+			t.Fatalf("Unexpected error %v", resp.Err())
+		}
+		if e.SQLState != "" || e.Message != "SELECT: no such table 'x'" {
+			t.Errorf("Unexpected error %+v", e)
+		}
+	})
+
+	t.Run("Verify Table returns the last result table", func(t *testing.T) {
+		resp, err := ParseResponse("&1 2 1 1 1\n% sys.t # table_name\n% a # name\n% int # type\n% 1 # length\n[ 1\t]\n" +
+			"&1 3 1 1 1\n% sys.u # table_name\n% c # name\n% int # type\n% 1 # length\n[ 2\t]\n&2 1 -1\n")
+		if err != nil {
+			t.Fatal(err)
+		}
+		table := resp.Table()
+		if table == nil || table.QueryId != 3 || table.Schema[0].ColumnName != "c" || table.Tuples[0] != "[ 2\t]" {
+			t.Errorf("Unexpected table %+v", table)
+		}
+		if resp, _ := ParseResponse("&2 1 -1\n"); resp.Table() != nil {
+			t.Error("Unexpected table in update response")
+		}
+	})
+
+	t.Run("Verify ParseResponse with unknown response", func(t *testing.T) {
+		_, err := ParseResponse("?unknown")
+		if err == nil {
+			t.Error("ParseResponse did not fail as expected")
+		}
+	})
+}
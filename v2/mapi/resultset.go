@@ -7,7 +7,6 @@ package mapi
 import (
 	"bytes"
 	"fmt"
-	"strconv"
 	"strings"
 )
 
@@ -38,129 +37,91 @@ type ResultSet struct {
 	Rows [][]Value
 }
 
+// StoreResult parses the raw reply of the server and stores it in the
+// resultset.
 func (s *ResultSet) StoreResult(r string) error {
-	var columnNames []string
-	var columnTypes []string
-	var displaySizes []int
-	var internalSizes []int
-	var precisions []int
-	var scales []int
-	var nullOks []int
-
-	for _, line := range strings.Split(r, "\n") {
-		if strings.HasPrefix(line, mapi_MSG_INFO) {
-			// TODO log
-
-		} else if strings.HasPrefix(line, mapi_MSG_QPREPARE) {
-			t := strings.Split(strings.TrimSpace(line[2:]), " ")
-			s.Metadata.ExecId, _ = strconv.Atoi(t[0])
-			return nil
-
-		} else if strings.HasPrefix(line, mapi_MSG_QTABLE) {
-			t := strings.Split(strings.TrimSpace(line[2:]), " ")
-			s.Metadata.QueryId, _ = strconv.Atoi(t[0])
-			s.Metadata.RowCount, _ = strconv.Atoi(t[1])
-			s.Metadata.ColumnCount, _ = strconv.Atoi(t[2])
-
-			columnNames = make([]string, s.Metadata.ColumnCount)
-			columnTypes = make([]string, s.Metadata.ColumnCount)
-			displaySizes = make([]int, s.Metadata.ColumnCount)
-			internalSizes = make([]int, s.Metadata.ColumnCount)
-			precisions = make([]int, s.Metadata.ColumnCount)
-			scales = make([]int, s.Metadata.ColumnCount)
-			nullOks = make([]int, s.Metadata.ColumnCount)
-
-		} else if strings.HasPrefix(line, mapi_MSG_TUPLE) {
-			v, err := s.parseTuple(line)
-			if err != nil {
-				return err
-			}
-			s.Rows = append(s.Rows, v)
+	resp, err := ParseResponse(r)
+	if err != nil {
+		return err
+	}
+	return s.StoreResponse(resp)
+}
 
-		} else if strings.HasPrefix(line, mapi_MSG_QBLOCK) {
-			s.Rows = make([][]Value, 0)
+// StoreResponse stores the parts of the response in the resultset. When the
+// response contains more than one part, the metadata of the last part is
+// kept.
+func (s *ResultSet) StoreResponse(resp *Response) error {
+	return s.store(resp, true)
+}
 
-		} else if strings.HasPrefix(line, mapi_MSG_QSCHEMA) {
-			s.Metadata.Offset = 0
-			s.Rows = make([][]Value, 0)
-			s.Metadata.LastRowId = 0
-			s.Schema = nil
-			s.Metadata.RowCount = 0
+// StoreMetadata stores the metadata and the schema of the response, like
+// StoreResponse, but does not convert the rows. Use Response.Tuples to get
+// the unconverted rows.
+func (s *ResultSet) StoreMetadata(resp *Response) error {
+	return s.store(resp, false)
+}
 
-		} else if strings.HasPrefix(line, mapi_MSG_QUPDATE) {
-			t := strings.Split(strings.TrimSpace(line[2:]), " ")
-			s.Metadata.RowCount, _ = strconv.Atoi(t[0])
-			s.Metadata.LastRowId, _ = strconv.Atoi(t[1])
+func (s *ResultSet) store(resp *Response, convert bool) error {
+	for _, part := range resp.Parts {
+		switch p := part.(type) {
+		case *PrepareResult:
+			s.Metadata.ExecId = p.ExecId
+			return nil
 
-		} else if strings.HasPrefix(line, mapi_MSG_QTRANS) {
+		case *ResultTable:
+			s.Metadata.QueryId = p.QueryId
+			s.Metadata.RowCount = p.RowCount
+			s.Metadata.ColumnCount = p.ColumnCount
 			s.Metadata.Offset = 0
-			s.Rows = make([][]Value, 0)
 			s.Metadata.LastRowId = 0
-			s.Schema = nil
-			s.Metadata.RowCount = 0
-
-		} else if strings.HasPrefix(line, mapi_MSG_HEADER) {
-			t := strings.Split(line[1:], "#")
-			data := strings.TrimSpace(t[0])
-			identity := strings.TrimSpace(t[1])
-
-			values := make([]string, 0)
-			for _, value := range strings.Split(data, ",") {
-				values = append(values, strings.TrimSpace(value))
+			s.Schema = p.Schema
+			if convert {
+				if err := s.storeTuples(p.Tuples); err != nil {
+					return err
+				}
 			}
 
-			if identity == "name" {
-				columnNames = values
-
-			} else if identity == "type" {
-				columnTypes = values
-
-			} else if identity == "typesizes" {
-				sizes := make([][]int, len(values))
-				for i, value := range values {
-					s := make([]int, 0)
-					for _, v := range strings.Split(value, " ") {
-						val, _ := strconv.Atoi(v)
-						s = append(s, val)
-					}
-					internalSizes[i] = s[0]
-					sizes[i] = s
-				}
-				for j, t := range columnTypes {
-					if t == "decimal" {
-						precisions[j] = sizes[j][0]
-						scales[j] = sizes[j][1]
-					}
-				}
-			} else if identity == "length" {
-				for i, value := range values {
-					s := make([]int, 0)
-					for _, v := range strings.Split(value, " ") {
-						val, _ := strconv.Atoi(v)
-						s = append(s, val)
-					}
-					displaySizes[i] = s[0]
+		case *ResultBlock:
+			s.Metadata.Offset = p.Offset
+			if convert {
+				if err := s.storeTuples(p.Tuples); err != nil {
+					return err
 				}
 			}
 
-			s.updateSchema(columnNames, columnTypes, displaySizes,
-				internalSizes, precisions, scales, nullOks)
+		case *UpdateCount:
+			s.Metadata.RowCount = p.RowCount
+			s.Metadata.LastRowId = p.LastRowId
+
+		case *SchemaChange, *TransactionChange:
 			s.Metadata.Offset = 0
+			s.Rows = make([][]Value, 0)
 			s.Metadata.LastRowId = 0
+			s.Schema = nil
+			s.Metadata.RowCount = 0
 
-		} else if strings.HasPrefix(line, mapi_MSG_PROMPT) {
-			return nil
-
-		} else if strings.HasPrefix(line, mapi_MSG_ERROR) {
-			return fmt.Errorf("mapi: database error: %s", line[1:])
+		case *Error:
+			return p
 		}
 	}
 
-	return fmt.Errorf("mapi: unknown state: %s", r)
+	return nil
+}
+
+func (s *ResultSet) storeTuples(tuples []string) error {
+	s.Rows = make([][]Value, 0, len(tuples))
+	for _, line := range tuples {
+		v, err := s.parseTuple(line)
+		if err != nil {
+			return err
+		}
+		s.Rows = append(s.Rows, v)
+	}
+	return nil
 }
 
 func (s *ResultSet) parseTuple(d string) ([]Value, error) {
-	items := strings.Split(d[1:len(d)-1], ",\t")
+	items := splitTuple(d)
 	if len(items) != len(s.Schema) {
 		return nil, fmt.Errorf("mapi: length of row doesn't match header")
 	}
@@ -176,25 +137,24 @@ func (s *ResultSet) parseTuple(d string) ([]Value, error) {
 	return v, nil
 }
 
-func (s *ResultSet) updateSchema(
-	columnNames, columnTypes []string, displaySizes,
-	internalSizes, precisions, scales, nullOks []int) {
-
-	d := make([]TableElement, len(columnNames))
-	for i, columnName := range columnNames {
-		desc := TableElement{
-			ColumnName:   columnName,
-			ColumnType:   columnTypes[i],
-			DisplaySize:  displaySizes[i],
-			InternalSize: internalSizes[i],
-			Precision:    precisions[i],
-			Scale:        scales[i],
-			NullOk:       nullOks[i],
+// splitTuple returns the fields of a row, as it is sent by the server.
+func splitTuple(d string) []string {
+	return strings.Split(d[1:len(d)-1], ",\t")
+}
+
+// AppendFields appends the fields of a row, as it is sent by the server, to
+// dst. The fields are not converted, so they can be passed to ConvertValue
+// or AppendText. When dst has enough capacity, nothing is allocated.
+func AppendFields(dst []string, d string) []string {
+	d = d[1 : len(d)-1]
+	for {
+		i := strings.Index(d, ",\t")
+		if i < 0 {
+			return append(dst, d)
 		}
-		d[i] = desc
+		dst = append(dst, d[:i])
+		d = d[i+2:]
 	}
-
-	s.Schema = d
 }
 
 func (s *ResultSet) convert(value, dataType string) (Value, error) {
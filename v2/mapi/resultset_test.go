@@ -99,3 +99,53 @@ func TestResultSetStoreResult(t *testing.T) {
 	})
 
 }
+
+func TestResultSetStoreMetadata(t *testing.T) {
+	// The value of the column is invalid, it must not be converted
+	resp, err := ParseResponse(`&1 2 3 1 2 0 201 169 7
+% sys.test1 # table_name
+% id # name
+% int # type
+% 3 # length
+% 32 0 # typesizes
+[ abc	]
+[ def	]
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var r ResultSet
+	if err := r.StoreMetadata(resp); err != nil {
+		t.Fatal(err)
+	}
+	if r.Metadata.QueryId != 2 || r.Metadata.RowCount != 3 || len(r.Schema) != 1 {
+		t.Errorf("Unexpected metadata %+v %+v", r.Metadata, r.Schema)
+	}
+	if r.Rows != nil {
+		t.Errorf("Unexpected rows %v", r.Rows)
+	}
+	if len(resp.Tuples()) != 2 {
+		t.Errorf("Unexpected tuples %q", resp.Tuples())
+	}
+
+	if err := r.StoreResponse(resp); err == nil {
+		t.Error("Conversion of invalid value did not fail")
+	}
+}
+
+func TestAppendFields(t *testing.T) {
+	fields := AppendFields(nil, "[ 1,\t\"name, with\tcomma\",\tNULL\t]")
+	if len(fields) != 3 {
+		t.Fatalf("Unexpected number of fields %d", len(fields))
+	}
+	if fields[0] != " 1" || fields[1] != "\"name, with\tcomma\"" || fields[2] != "NULL\t" {
+		t.Errorf("Unexpected fields %q", fields)
+	}
+
+	allocs := testing.AllocsPerRun(10, func() {
+		fields = AppendFields(fields[:0], "[ 1,\t2,\t3\t]")
+	})
+	if allocs != 0 {
+		t.Errorf("Unexpected number of allocations %v", allocs)
+	}
+}
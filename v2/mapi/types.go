@@ -6,12 +6,15 @@ package mapi
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
 // Time represents MonetDB's Time datatype.
 type Time struct {
 	Hour, Min, Sec int
+	// Nsec is the fraction of the second in nanoseconds
+	Nsec int
 }
 
 // Time represents MonetDB's Date datatype.
@@ -22,14 +25,19 @@ type Date struct {
 }
 
 // String returns a string representation of a Time
-// in the form "HH:YY:MM".
+// in the form "HH:MM:SS", followed by the microseconds
+// without trailing zeros, for example "10:20:30.1234".
 func (t Time) String() string {
-	return fmt.Sprintf("%02d:%02d:%02d", t.Hour, t.Min, t.Sec)
+	s := fmt.Sprintf("%02d:%02d:%02d", t.Hour, t.Min, t.Sec)
+	if usec := t.Nsec / 1000; usec != 0 {
+		s += strings.TrimRight(fmt.Sprintf(".%06d", usec), "0")
+	}
+	return s
 }
 
 // Time converts to time.Time. The date is set to January 1, 1970.
 func (t Time) Time() time.Time {
-	return time.Date(1970, time.January, 1, t.Hour, t.Min, t.Sec, 0, time.UTC)
+	return time.Date(1970, time.January, 1, t.Hour, t.Min, t.Sec, t.Nsec, time.UTC)
 }
 
 // String returns a string representation of a Date
@@ -46,7 +54,7 @@ func (d Date) Time() time.Time {
 // GetTime takes the clock part of a time.Time and put it in a Time
 func GetTime(t time.Time) Time {
 	hour, min, sec := t.Clock()
-	return Time{hour, min, sec}
+	return Time{hour, min, sec, t.Nanosecond()}
 }
 
 // GetDate takes the date part of a time.Time and put it in a Date
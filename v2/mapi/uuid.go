@@ -0,0 +1,96 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package mapi
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+)
+
+// UUID represents MonetDB's uuid datatype.
+type UUID [16]byte
+
+// ParseUUID parses a UUID in the form "xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx",
+// or the same 32 hexadecimal digits without hyphens.
+func ParseUUID(s string) (UUID, error) {
+	var u UUID
+	digits := s
+	if len(s) == 36 {
+		if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return u, fmt.Errorf("mapi: invalid uuid %q", s)
+		}
+		digits = s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:]
+	}
+	if len(digits) != 32 {
+		return u, fmt.Errorf("mapi: invalid uuid %q", s)
+	}
+	if _, err := hex.Decode(u[:], []byte(digits)); err != nil {
+		return u, fmt.Errorf("mapi: invalid uuid %q", s)
+	}
+	return u, nil
+}
+
+// String returns the UUID in the form "xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx".
+func (u UUID) String() string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf[:])
+}
+
+// Scan implements the sql.Scanner interface.
+func (u *UUID) Scan(src any) error {
+	var err error
+	switch v := src.(type) {
+	case UUID:
+		*u = v
+	case [16]byte:
+		*u = v
+	case string:
+		*u, err = ParseUUID(v)
+	case []byte:
+		if len(v) == 16 {
+			copy(u[:], v)
+		} else {
+			*u, err = ParseUUID(string(v))
+		}
+	case nil:
+		err = fmt.Errorf("mapi: cannot scan NULL into UUID")
+	default:
+		err = fmt.Errorf("mapi: cannot scan %T into UUID", src)
+	}
+	return err
+}
+
+// Value implements the driver.Valuer interface.
+func (u UUID) Value() (driver.Value, error) {
+	return u.String(), nil
+}
+
+func toUUID(v string) (Value, error) {
+	if len(v) >= 2 && v[0] == '"' && v[len(v)-1] == '"' {
+		v = v[1 : len(v)-1]
+	}
+	return ParseUUID(v)
+}
+
+func toUUIDString(v Value) (string, error) {
+	switch val := v.(type) {
+	case UUID:
+		return toQuotedString(val.String())
+	case [16]byte:
+		return toQuotedString(UUID(val).String())
+	default:
+		return "", fmt.Errorf("mapi: unsupported type")
+	}
+}
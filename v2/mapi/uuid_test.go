@@ -0,0 +1,64 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package mapi
+
+import (
+	"testing"
+)
+
+func TestParseUUID(t *testing.T) {
+	e := UUID{0x6a, 0x6d, 0x3e, 0x4e, 0x1f, 0x2b, 0x4c, 0x3d, 0x9e, 0x8f, 0x0a, 0x1b, 0x2c, 0x3d, 0x4e, 0x5f}
+
+	for _, v := range []string{
+		"6a6d3e4e-1f2b-4c3d-9e8f-0a1b2c3d4e5f",
+		"6A6D3E4E-1F2B-4C3D-9E8F-0A1B2C3D4E5F",
+		"6a6d3e4e1f2b4c3d9e8f0a1b2c3d4e5f",
+	} {
+		u, err := ParseUUID(v)
+		if err != nil {
+			t.Errorf("Error parsing uuid: %s -> %v", v, err)
+		} else if u != e {
+			t.Errorf("Invalid value: %s, expected: %s", u, e)
+		}
+	}
+	if e.String() != "6a6d3e4e-1f2b-4c3d-9e8f-0a1b2c3d4e5f" {
+		t.Errorf("Invalid value: %s", e.String())
+	}
+
+	for _, v := range []string{"", "6a6d3e4e-1f2b-4c3d-9e8f", "6a6d3e4e11f2b-4c3d-9e8f-0a1b2c3d4e5f", "xa6d3e4e-1f2b-4c3d-9e8f-0a1b2c3d4e5f"} {
+		if _, err := ParseUUID(v); err == nil {
+			t.Errorf("Invalid uuid %q did not fail", v)
+		}
+	}
+}
+
+func TestUUIDConversion(t *testing.T) {
+	s := "6a6d3e4e-1f2b-4c3d-9e8f-0a1b2c3d4e5f"
+	v, err := convertToGo(s, "uuid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, ok := v.(UUID)
+	if !ok || u.String() != s {
+		t.Errorf("Invalid value: %v, expected: %s", v, s)
+	}
+
+	for _, p := range []Value{u, &u, [16]byte(u)} {
+		str, err := ConvertToMonet(p)
+		if err != nil || str != "'"+s+"'" {
+			t.Errorf("Invalid value: %s (%v), expected: '%s'", str, err, s)
+		}
+	}
+
+	var scanned UUID
+	for _, src := range []any{s, []byte(s), u, [16]byte(u), u[:]} {
+		if err := scanned.Scan(src); err != nil || scanned != u {
+			t.Errorf("Invalid value: %s (%v) for %T", scanned, err, src)
+		}
+	}
+	if err := scanned.Scan(nil); err == nil {
+		t.Error("Scanning NULL did not fail")
+	}
+}
@@ -0,0 +1,110 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package monetdb
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestNullIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	db, err := sql.Open("monetdb", "monetdb:monetdb@localhost:50000/monetdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var nilString *string
+	var nilInt *int64
+	var nilTime *time.Time
+
+	type tc struct {
+		columnType string
+		param      any
+		dest       func() (any, func() bool)
+	}
+	nullString := func() (any, func() bool) {
+		var v sql.NullString
+		return &v, func() bool { return v.Valid }
+	}
+	nullInt := func() (any, func() bool) {
+		var v sql.NullInt64
+		return &v, func() bool { return v.Valid }
+	}
+	nullFloat := func() (any, func() bool) {
+		var v sql.NullFloat64
+		return &v, func() bool { return v.Valid }
+	}
+	nullBool := func() (any, func() bool) {
+		var v sql.NullBool
+		return &v, func() bool { return v.Valid }
+	}
+	nullTime := func() (any, func() bool) {
+		var v sql.NullTime
+		return &v, func() bool { return v.Valid }
+	}
+	nullBytes := func() (any, func() bool) {
+		var v []byte
+		return &v, func() bool { return v != nil }
+	}
+	var tcs = []tc{
+		{"varchar(16)", nilString, nullString},
+		{"char(4)", sql.NullString{}, nullString},
+		{"clob", nil, nullString},
+		{"tinyint", sql.NullInt16{}, nullInt},
+		{"smallint", nilInt, nullInt},
+		{"int", sql.NullInt32{}, nullInt},
+		{"bigint", sql.NullInt64{}, nullInt},
+		{"real", sql.NullFloat64{}, nullFloat},
+		{"double", nil, nullFloat},
+		{"decimal(10,2)", sql.NullFloat64{}, nullFloat},
+		{"boolean", sql.NullBool{}, nullBool},
+		{"date", nilTime, nullString},
+		{"time", sql.NullTime{}, nullString},
+		{"timestamp", nilTime, nullTime},
+		{"timestamptz", sql.NullTime{}, nullTime},
+		{"blob", []byte(nil), nullBytes},
+	}
+
+	for _, c := range tcs {
+		t.Run(fmt.Sprintf("Round trip NULL %s", c.columnType), func(t *testing.T) {
+			if _, err := db.Exec(fmt.Sprintf("create table test_null ( v %s )", c.columnType)); err != nil {
+				t.Fatal(err)
+			}
+			defer db.Exec("drop table test_null")
+
+			stmt, err := db.Prepare("insert into test_null values ( ? )")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer stmt.Close()
+			if _, err := stmt.Exec(c.param); err != nil {
+				t.Fatal(err)
+			}
+
+			var isNull bool
+			if err := db.QueryRow("select v is null from test_null").Scan(&isNull); err != nil {
+				t.Fatal(err)
+			}
+			if !isNull {
+				t.Error("Value was not stored as NULL")
+			}
+
+			dest, valid := c.dest()
+			if err := db.QueryRow("select v from test_null").Scan(dest); err != nil {
+				t.Fatal(err)
+			}
+			if valid() {
+				t.Error("NULL was scanned as a valid value")
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@
 package monetdb
 
 import (
+	"bytes"
 	"database/sql"
 	"testing"
 )
@@ -339,3 +340,64 @@ func TestMultipleParamIntegration(t *testing.T) {
 		}
 	})
 }
+
+func TestBlobParamIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	db, err := sql.Open("monetdb", "monetdb:monetdb@localhost:50000/monetdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	payload := make([]byte, 256)
+	for i := range payload {
+		payload[i] = byte(i)
+	}
+
+	t.Run("Exec create table", func(t *testing.T) {
+		_, err := db.Exec("create table test_blob ( id int, data blob )")
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Exec insert binary data", func(t *testing.T) {
+		stmt, err := db.Prepare("insert into test_blob values ( ?, ? )")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stmt.Close()
+		if _, err := stmt.Exec(1, payload); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := stmt.Exec(2, []byte{}); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Query binary data", func(t *testing.T) {
+		var data []byte
+		if err := db.QueryRow("select data from test_blob where id = 1").Scan(&data); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(data, payload) {
+			t.Errorf("Unexpected data %x", data)
+		}
+		if err := db.QueryRow("select data from test_blob where id = 2").Scan(&data); err != nil {
+			t.Fatal(err)
+		}
+		if data == nil || len(data) != 0 {
+			t.Errorf("Unexpected data %x, expected an empty blob", data)
+		}
+	})
+
+	t.Run("Exec drop table", func(t *testing.T) {
+		_, err := db.Exec("drop table test_blob")
+		if err != nil {
+			t.Error(err)
+		}
+	})
+}
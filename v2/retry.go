@@ -0,0 +1,116 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package monetdb
+
+import (
+	"context"
+	"database/sql"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// DefaultMaxAttempts is the number of times RunInTx runs the transaction
+// when RetryOptions.MaxAttempts is not set.
+const DefaultMaxAttempts = 5
+
+// RetryOptions configures how RunInTx retries a transaction.
+type RetryOptions struct {
+	// TxOptions are passed to BeginTx for every attempt.
+	TxOptions *sql.TxOptions
+	// MaxAttempts is the maximum number of times the transaction is run,
+	// including the first attempt. When it is zero, DefaultMaxAttempts is used.
+	MaxAttempts int
+	// Backoff returns the time to wait before the given attempt. The second
+	// attempt is the first retry. When it is nil, an exponential backoff
+	// with jitter is used, starting at 10ms.
+	Backoff func(attempt int) time.Duration
+}
+
+// RunInTx runs fn inside a transaction and commits it. MonetDB uses
+// optimistic concurrency control, so a transaction can fail because of a
+// conflict with a concurrent transaction, usually when it is committed.
+// When that happens, the transaction is rolled back and fn is run again
+// in a new transaction, until the maximum number of attempts is reached.
+//
+// Errors that are not caused by a concurrency conflict are returned
+// unchanged, without a retry. When the context is done while waiting for
+// the next attempt, the error of the last attempt is returned.
+//
+// fn must not commit or roll back the transaction, and it should not have
+// side effects outside of the transaction, because it can be run more than
+// once.
+func RunInTx(ctx context.Context, db *sql.DB, opts *RetryOptions, fn func(*sql.Tx) error) error {
+	if opts == nil {
+		opts = &RetryOptions{}
+	}
+	maxAttempts := opts.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+	backoff := opts.Backoff
+	if backoff == nil {
+		backoff = defaultBackoff
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := runTx(ctx, db, opts.TxOptions, fn)
+		if err == nil || !IsConcurrencyConflict(err) || attempt >= maxAttempts {
+			return err
+		}
+
+		timer := time.NewTimer(backoff(attempt + 1))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+func runTx(ctx context.Context, db *sql.DB, opts *sql.TxOptions, fn func(*sql.Tx) error) (err error) {
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// jitter is the source of the random jitter of defaultBackoff. Before Go
+// 1.20 the global source of math/rand is seeded with the same value in
+// every process, so every process would wait the same times.
+var jitter = struct {
+	sync.Mutex
+	rand *rand.Rand
+}{rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+
+// defaultBackoff doubles the waiting time for every attempt, up to one
+// second. A random jitter prevents that conflicting transactions are
+// retried at the same moment.
+func defaultBackoff(attempt int) time.Duration {
+	d := 10 * time.Millisecond
+	for i := 2; i < attempt && d < time.Second; i++ {
+		d *= 2
+	}
+	if d > time.Second {
+		d = time.Second
+	}
+	jitter.Lock()
+	n := jitter.rand.Int63n(int64(d/2) + 1)
+	jitter.Unlock()
+	return d/2 + time.Duration(n)
+}
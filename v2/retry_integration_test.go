@@ -0,0 +1,88 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package monetdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRunInTxIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	db, err := sql.Open("monetdb", "monetdb:monetdb@localhost:50000/monetdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	opts := &RetryOptions{
+		Backoff: func(int) time.Duration { return time.Millisecond },
+	}
+
+	t.Run("Exec create table", func(t *testing.T) {
+		if _, err := db.Exec("create table retry_test1 (id int primary key, v int)"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Exec("insert into retry_test1 values (1, 0)"); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Conflicting transaction is retried", func(t *testing.T) {
+		attempts := 0
+		err := RunInTx(ctx, db, opts, func(tx *sql.Tx) error {
+			attempts++
+			if attempts == 1 {
+				// A concurrent transaction changes the row after this transaction started
+				if _, err := db.Exec("update retry_test1 set v = v + 10 where id = 1"); err != nil {
+					return err
+				}
+			}
+			_, err := tx.Exec("update retry_test1 set v = v + 1 where id = 1")
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if attempts != 2 {
+			t.Errorf("Unexpected number of attempts %d", attempts)
+		}
+		var v int
+		if err := db.QueryRow("select v from retry_test1 where id = 1").Scan(&v); err != nil {
+			t.Fatal(err)
+		}
+		if v != 11 {
+			t.Errorf("Unexpected value %d", v)
+		}
+	})
+
+	t.Run("Other errors are not retried", func(t *testing.T) {
+		attempts := 0
+		failure := errors.New("failure")
+		err := RunInTx(ctx, db, opts, func(tx *sql.Tx) error {
+			attempts++
+			return failure
+		})
+		if err != failure {
+			t.Errorf("Unexpected error %v", err)
+		}
+		if attempts != 1 {
+			t.Errorf("Unexpected number of attempts %d", attempts)
+		}
+	})
+
+	t.Run("Exec drop table", func(t *testing.T) {
+		if _, err := db.Exec("drop table retry_test1"); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
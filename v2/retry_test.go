@@ -0,0 +1,50 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package monetdb
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDefaultBackoff(t *testing.T) {
+	type tc struct {
+		attempt int
+		min     time.Duration
+		max     time.Duration
+	}
+	var tcs = []tc{
+		{2, 5 * time.Millisecond, 10 * time.Millisecond},
+		{3, 10 * time.Millisecond, 20 * time.Millisecond},
+		{4, 20 * time.Millisecond, 40 * time.Millisecond},
+		{20, 500 * time.Millisecond, time.Second},
+	}
+
+	for _, c := range tcs {
+		d := defaultBackoff(c.attempt)
+		if d < c.min || d > c.max {
+			t.Errorf("Invalid backoff for attempt %d: %v, expected between %v and %v", c.attempt, d, c.min, c.max)
+		}
+	}
+}
+
+func TestDefaultBackoffConcurrent(t *testing.T) {
+	// The jitter source is shared by all transactions, run with -race
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if d := defaultBackoff(3); d < 10*time.Millisecond || d > 20*time.Millisecond {
+					t.Errorf("Invalid backoff %v", d)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -9,6 +9,9 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"math/big"
+	"net/netip"
+	"net/url"
 	"strings"
 	"reflect"
 	"time"
@@ -18,9 +21,12 @@ import (
 )
 
 type Rows struct {
-	conn        *mapi.MapiConn
-	resultset   *mapi.ResultSet
+	// ctx is the context of the query, it is also used to fetch the
+	// remaining blocks of the result set
+	ctx         context.Context
+	conn        *Conn
 	active      bool
+	serverOpen  bool
 	queryId     int
 	err         error
 
@@ -28,15 +34,23 @@ type Rows struct {
 	offset      int
 	lastRowId   int
 	rowCount    int
-	rows        [][]driver.Value
+	// tuples are the unconverted rows of the last block, they are converted
+	// when they are read
+	tuples      []string
 	schema      []mapi.TableElement
 	columns     []string
+
+	// fields and buf are reused for every row. The text values that are
+	// returned by Next point into buf, so they are only valid until the
+	// next call to Next, like sql.RawBytes.
+	fields      []string
+	buf         []byte
 }
 
-func newRows(c *mapi.MapiConn, r *mapi.ResultSet) *Rows {
+func newRows(ctx context.Context, c *Conn) *Rows {
 	return &Rows{
+		ctx:       ctx,
 		conn:      c,
-		resultset: r,
 		active:    true,
 		err:       nil,
 
@@ -45,6 +59,36 @@ func newRows(c *mapi.MapiConn, r *mapi.ResultSet) *Rows {
 	}
 }
 
+// storeResponse stores the first block of the result of a query. The rows
+// and the metadata are taken from the same result table, the last one when
+// the query contains more than one statement. The rows are converted when
+// they are read.
+func (r *Rows) storeResponse(resp *mapi.Response) error {
+	if err := resp.Err(); err != nil {
+		return err
+	}
+	table := resp.Table()
+	if table == nil {
+		return nil
+	}
+	for _, p := range resp.Parts {
+		// Only the last result is returned, the other results are released
+		if t, ok := p.(*mapi.ResultTable); ok && t != table && t.RowCount > len(t.Tuples) {
+			r.conn.closeResult(t.QueryId)
+		}
+	}
+	// We have gotten the first batch of the resultset. The RowCount is the total number of rows in the result.
+	// But we have only at most mapi.MAPI_ARRAY_SIZE rows available.
+	r.queryId = table.QueryId
+	r.rowCount = table.RowCount
+	r.offset = 0
+	r.tuples = table.Tuples
+	r.schema = table.Schema
+	// The server keeps the result set open when it did not fit in the first block
+	r.serverOpen = r.rowCount > len(r.tuples)
+	return nil
+}
+
 func (r *Rows) Columns() []string {
 	if r.columns == nil {
 		r.columns = make([]string, len(r.schema))
@@ -56,6 +100,9 @@ func (r *Rows) Columns() []string {
 }
 
 func (r *Rows) Close() error {
+	if r.active && r.serverOpen {
+		r.conn.closeResult(r.queryId)
+	}
 	r.active = false
 	return nil
 }
@@ -71,26 +118,96 @@ func (r *Rows) Next(dest []driver.Value) error {
 	if r.rowNum >= r.rowCount {
 		return io.EOF
 	}
+	if err := r.ctx.Err(); err != nil {
+		return err
+	}
 
-	if r.rowNum >= r.offset+len(r.rows) {
+	if !r.buffered(r.rowNum) {
 		err := r.fetchNext()
 		if err != nil {
 			return err
 		}
 	}
 
-	for i, v := range r.rows[r.rowNum-r.offset] {
+	if err := r.scanRow(r.tuples[r.rowNum-r.offset], dest); err != nil {
+		return err
+	}
+	r.rowNum += 1
+
+	return nil
+}
+
+// scanRow converts the fields of the row into dest. The values of text
+// columns are copied into the buffer of the rows, instead of allocating a
+// new slice for every value.
+//
+// Every type is returned as its Go value, as reported by
+// ColumnTypeScanType, except for decimals. A float64 can not hold every
+// decimal, so they are returned as text, which can still be scanned into a
+// float64 or a string, like the float64 that was returned before.
+func (r *Rows) scanRow(tuple string, dest []driver.Value) error {
+	r.fields = mapi.AppendFields(r.fields[:0], tuple)
+	if len(r.fields) != len(r.schema) {
+		return fmt.Errorf("mapi: length of row doesn't match header")
+	}
+	r.buf = r.buf[:0]
+	for i, field := range r.fields {
+		dataType := r.schema[i].ColumnType
+		isNull := strings.TrimSpace(field) == "NULL"
+		if mapi.IsText(dataType) && !isNull {
+			start := len(r.buf)
+			var err error
+			r.buf, err = mapi.AppendText(r.buf, field)
+			if err != nil {
+				return err
+			}
+			dest[i] = r.buf[start:len(r.buf):len(r.buf)]
+			continue
+		}
+		if mapi.IsRaw(dataType) && !isNull {
+			// Decimals are returned as text, so they can be scanned into a
+			// Decimal without loss of precision
+			start := len(r.buf)
+			var err error
+			r.buf, err = mapi.AppendRaw(r.buf, field)
+			if err != nil {
+				return err
+			}
+			dest[i] = r.buf[start:len(r.buf):len(r.buf)]
+			continue
+		}
+
+		v, err := mapi.ConvertValue(field, dataType)
+		if err != nil {
+			return err
+		}
 		if vv, ok := v.(string); ok {
 			dest[i] = []byte(vv)
 		} else {
 			dest[i] = v
 		}
 	}
-	r.rowNum += 1
-
 	return nil
 }
 
+// convertRow converts the fields of the row into new values, that stay
+// valid after the next row is read.
+func (r *Rows) convertRow(tuple string) ([]driver.Value, error) {
+	r.fields = mapi.AppendFields(r.fields[:0], tuple)
+	if len(r.fields) != len(r.schema) {
+		return nil, fmt.Errorf("mapi: length of row doesn't match header")
+	}
+	row := make([]driver.Value, len(r.fields))
+	for i, field := range r.fields {
+		v, err := mapi.ConvertValue(field, r.schema[i].ColumnType)
+		if err != nil {
+			return nil, err
+		}
+		row[i] = v
+	}
+	return row, nil
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
@@ -100,46 +217,63 @@ func min(a, b int) int {
 }
 
 // This function call to FetchNext connects to the database and can potentially take a long time. Therefore
-// we want to be able to cancel it, so we run it inside a goroutine.
-func (s *Rows) mapiDo(ctx context.Context, amount int) (string, error) {
+// we want to be able to cancel it, so we run it inside a goroutine. When the context of the query is done
+// before the block is received, the fetch is interrupted and the connection can not be used anymore.
+func (s *Rows) mapiDo(ctx context.Context, offset int, amount int) (*mapi.Response, error) {
+	if !s.conn.IsValid() {
+		return nil, driver.ErrBadConn
+	}
 	type res struct {
-		resultstring string;
+		response *mapi.Response;
 		err error
 	}
 	c := make(chan res, 1)
 
     go func() {
-		r, err := s.conn.FetchNext(s.queryId, s.offset, amount)
+		r, err := s.conn.mapi.FetchNext(s.queryId, offset, amount)
 		result := res{r, err}
 		c <- result
 		}()
 
     select {
     case <-ctx.Done():
-        <-c // Wait for the goroutine to return. Later we need to cancel the query on the database
-        return "", ctx.Err()
+        s.conn.mapi.Interrupt()
+        result := <-c // Wait for the goroutine to return. Later we need to cancel the query on the database
+        s.conn.checkErr(result.err)
+        return nil, ctx.Err()
     case result := <-c:
-        return result.resultstring, result.err
+        return result.response, s.conn.checkErr(result.err)
     }
 }
 
+// buffered reports whether the row is in the block that was received last.
+func (r *Rows) buffered(row int) bool {
+	return row >= r.offset && row < r.offset+len(r.tuples)
+}
+
 func (r *Rows) fetchNext() error {
 	if r.rowNum >= r.rowCount {
 		return io.EOF
 	}
 
-	r.offset += len(r.rows)
 	end := min(r.rowCount, r.rowNum+mapi.MAPI_ARRAY_SIZE)
-	amount := end - r.offset
+	return r.fetchBlock(r.rowNum, end-r.rowNum)
+}
 
-	res, err := r.mapiDo(context.Background(), amount)
+// fetchBlock replaces the buffered rows with the rows of the result set
+// that start at the offset.
+func (r *Rows) fetchBlock(offset int, amount int) error {
+	res, err := r.mapiDo(r.ctx, offset, amount)
 	if err != nil {
 		return err
 	}
 
-	r.resultset.StoreResult(res)
-	r.rows = convertRows(r.resultset.Rows, r.resultset.Metadata.ColumnCount)
-	r.schema = r.resultset.Schema
+	tuples := res.Tuples()
+	if len(tuples) != amount {
+		return fmt.Errorf("monetdb: unexpected number of rows %d, expected: %d", len(tuples), amount)
+	}
+	r.offset = offset
+	r.tuples = tuples
 
 	return nil
 }
@@ -186,11 +320,14 @@ func (r *Rows) ColumnTypeScanType(index int) reflect.Type {
 	switch r.schema[index].ColumnType {
 	case mapi.MDB_VARCHAR,
 		mapi.MDB_CHAR,
-		mapi.MDB_CLOB,
-		mapi.MDB_INTERVAL,
-		mapi.MDB_MONTH_INTERVAL,
-		mapi.MDB_SEC_INTERVAL :
+		mapi.MDB_CLOB :
 		scantype = reflect.TypeOf("")
+	case mapi.MDB_INTERVAL,
+		mapi.MDB_SEC_INTERVAL,
+		mapi.MDB_DAY_INTERVAL :
+		scantype = reflect.TypeOf(time.Duration(0))
+	case mapi.MDB_MONTH_INTERVAL :
+		scantype = reflect.TypeOf(MonthInterval(0))
 	case mapi.MDB_NULL :
 		scantype = reflect.TypeOf(nil)
 	case mapi.MDB_BLOB :
@@ -200,8 +337,10 @@ func (r *Rows) ColumnTypeScanType(index int) reflect.Type {
 	case mapi.MDB_REAL,
 		mapi.MDB_FLOAT :
 		scantype = reflect.TypeOf(float32(0))
-	case mapi.MDB_DECIMAL,
-		mapi.MDB_DOUBLE :
+	case mapi.MDB_DECIMAL :
+		// Decimals are returned as text, like the text types
+		scantype = reflect.TypeOf("")
+	case mapi.MDB_DOUBLE :
 		scantype = reflect.TypeOf(float64(0))
 	case mapi.MDB_TINYINT :
 		scantype = reflect.TypeOf(int8(0))
@@ -212,15 +351,32 @@ func (r *Rows) ColumnTypeScanType(index int) reflect.Type {
 		mapi.MDB_MEDIUMINT,
 		mapi.MDB_WRD :
 		scantype = reflect.TypeOf(int32(0))
+	case mapi.MDB_JSON :
+		// The json text is returned like the text types
+		scantype = reflect.TypeOf("")
+	case mapi.MDB_INET :
+		scantype = reflect.TypeOf(netip.Prefix{})
+	case mapi.MDB_URL :
+		scantype = reflect.TypeOf((*url.URL)(nil))
+	case mapi.MDB_GEOMETRY,
+		mapi.MDB_GEOMETRYA,
+		mapi.MDB_MBR :
+		scantype = reflect.TypeOf(Geometry{})
+	case mapi.MDB_UUID :
+		scantype = reflect.TypeOf(UUID{})
+	case mapi.MDB_HUGEINT :
+		scantype = reflect.TypeOf((*big.Int)(nil))
 	case mapi.MDB_BIGINT,
-		mapi.MDB_HUGEINT,
 		mapi.MDB_SERIAL,
 		mapi.MDB_LONGINT :
 		scantype = reflect.TypeOf(int64(0))
-	case mapi.MDB_DATE,
-		mapi.MDB_TIME,
-		mapi.MDB_TIMESTAMP,
-		mapi.MDB_TIMESTAMPTZ :
+	case mapi.MDB_DATE :
+		scantype = reflect.TypeOf(Date{})
+	case mapi.MDB_TIME :
+		scantype = reflect.TypeOf(Time{})
+	case mapi.MDB_TIMESTAMP,
+		mapi.MDB_TIMESTAMPTZ,
+		mapi.MDB_TIMETZ :
 		scantype = reflect.TypeOf(time.Time{})
 	default:
 		scantype = reflect.TypeOf(nil)
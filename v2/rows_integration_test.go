@@ -5,6 +5,7 @@
 package monetdb
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"math"
@@ -149,6 +150,175 @@ func TestRowsIntegration(t *testing.T) {
 }
 
 
+func TestRowsInterleavedIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	db, err := sql.Open("monetdb", "monetdb:monetdb@localhost:50000/monetdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// The results are larger than one block, so the remaining rows are
+	// fetched while the other results are open
+	const n = 250
+
+	t.Run("Interleave fetches from several rows", func(t *testing.T) {
+		query := "select value, cast(value as varchar(10)) from sys.generate_series(0, %d)"
+		rows1, err := conn.QueryContext(ctx, fmt.Sprintf(query, n))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows1.Close()
+		rows2, err := conn.QueryContext(ctx, fmt.Sprintf("select value * 2 from sys.generate_series(0, %d)", n))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows2.Close()
+
+		for i := 0; i < n; i++ {
+			if !rows1.Next() || !rows2.Next() {
+				t.Fatalf("Unexpected end of rows at row %d: %v %v", i, rows1.Err(), rows2.Err())
+			}
+			var v1, v2 int
+			var s1 string
+			if err := rows1.Scan(&v1, &s1); err != nil {
+				t.Fatal(err)
+			}
+			if err := rows2.Scan(&v2); err != nil {
+				t.Fatal(err)
+			}
+			if v1 != i || s1 != fmt.Sprint(i) || v2 != 2*i {
+				t.Fatalf("Unexpected values %d %s %d at row %d", v1, s1, v2, i)
+			}
+		}
+		if rows1.Next() || rows2.Next() {
+			t.Error("Unexpected row after the end of the result")
+		}
+	})
+
+	t.Run("Run lookups while reading a parent query", func(t *testing.T) {
+		stmt, err := conn.PrepareContext(ctx, "select ? * 10")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stmt.Close()
+
+		parent, err := conn.QueryContext(ctx, fmt.Sprintf("select value from sys.generate_series(0, %d)", n))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer parent.Close()
+
+		count := 0
+		for parent.Next() {
+			var v int
+			if err := parent.Scan(&v); err != nil {
+				t.Fatal(err)
+			}
+			var lookup int
+			if err := stmt.QueryRowContext(ctx, v).Scan(&lookup); err != nil {
+				t.Fatal(err)
+			}
+			if lookup != v*10 {
+				t.Fatalf("Unexpected lookup %d for %d", lookup, v)
+			}
+			count++
+		}
+		if err := parent.Err(); err != nil {
+			t.Error(err)
+		}
+		if count != n {
+			t.Errorf("Unexpected number of rows %d, expected: %d", count, n)
+		}
+	})
+
+	t.Run("Execute a prepared statement while its rows are open", func(t *testing.T) {
+		stmt, err := conn.PrepareContext(ctx, "select value from sys.generate_series(0, ?)")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stmt.Close()
+
+		rows1, err := stmt.QueryContext(ctx, n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows1.Close()
+		rows2, err := stmt.QueryContext(ctx, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows2.Close()
+
+		count2 := 0
+		for rows2.Next() {
+			count2++
+		}
+		count1 := 0
+		for rows1.Next() {
+			var v int
+			if err := rows1.Scan(&v); err != nil {
+				t.Fatal(err)
+			}
+			if v != count1 {
+				t.Fatalf("Unexpected value %d, expected: %d", v, count1)
+			}
+			count1++
+		}
+		if count1 != n || count2 != 3 {
+			t.Errorf("Unexpected number of rows %d and %d", count1, count2)
+		}
+	})
+}
+
+func TestRowsRawBytesIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	db, err := sql.Open("monetdb", "monetdb:monetdb@localhost:50000/monetdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	t.Run("Scan text into RawBytes", func(t *testing.T) {
+		rows, err := db.Query("select 'name' || value, 'it''s' from sys.generate_series(0, 250)")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+
+		i := 0
+		for rows.Next() {
+			var name, quoted sql.RawBytes
+			if err := rows.Scan(&name, &quoted); err != nil {
+				t.Fatal(err)
+			}
+			if string(name) != fmt.Sprintf("name%d", i) || string(quoted) != "it's" {
+				t.Fatalf("Unexpected values %q %q at row %d", name, quoted, i)
+			}
+			i++
+		}
+		if err := rows.Err(); err != nil {
+			t.Error(err)
+		}
+		if i != 250 {
+			t.Errorf("Unexpected number of rows %d", i)
+		}
+	})
+}
+
 func TestColumnTypesIntegration(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test")
@@ -293,7 +463,7 @@ func TestColumnTypesIntegration(t *testing.T) {
 			[]int64{0, 0},
 			[]bool{false, false},
 			[]string{"DECIMAL", "DECIMAL"},
-			[]string{"float64", "float64"},
+			[]string{"string", "string"},
 			[]bool{true, true},
 			[]int64{18, 10},
 			[]int64{3, 5},
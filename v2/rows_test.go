@@ -0,0 +1,234 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package monetdb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+
+	"github.com/MonetDB/MonetDB-Go/v2/mapi"
+)
+
+func TestRowsScanText(t *testing.T) {
+	r := newRows(context.Background(), nil)
+	r.schema = []mapi.TableElement{
+		{ColumnName: "name", ColumnType: mapi.MDB_VARCHAR},
+		{ColumnName: "description", ColumnType: mapi.MDB_CLOB},
+	}
+	dest := make([]driver.Value, 2)
+
+	t.Run("Verify text values are decoded into the row buffer", func(t *testing.T) {
+		if err := r.scanRow("[ \"first\",\t\"it\\'s\"\t]", dest); err != nil {
+			t.Fatal(err)
+		}
+		name, ok1 := dest[0].([]byte)
+		description, ok2 := dest[1].([]byte)
+		if !ok1 || !ok2 || string(name) != "first" || string(description) != "it's" {
+			t.Errorf("Unexpected values %q %q", dest[0], dest[1])
+		}
+		// Appending to a value must not overwrite the next value
+		_ = append(name, 'x')
+		if string(description) != "it's" {
+			t.Errorf("Value was overwritten %q", description)
+		}
+	})
+
+	t.Run("Verify the row buffer is reused", func(t *testing.T) {
+		allocs := testing.AllocsPerRun(10, func() {
+			if err := r.scanRow("[ \"second\",\t\"value\"\t]", dest); err != nil {
+				t.Fatal(err)
+			}
+		})
+		// Only the slice headers are allocated, when they are stored in
+		// dest, the text itself is not copied
+		if allocs > 2 {
+			t.Errorf("Unexpected number of allocations %v", allocs)
+		}
+		name := dest[0].([]byte)
+		if string(name) != "second" || string(dest[1].([]byte)) != "value" {
+			t.Errorf("Unexpected values %q %q", dest[0], dest[1])
+		}
+		if &name[0] != &r.buf[0] {
+			t.Error("Value is not stored in the row buffer")
+		}
+	})
+}
+
+func TestRowsScanTextEscapes(t *testing.T) {
+	r := newRows(context.Background(), nil)
+	r.schema = []mapi.TableElement{
+		{ColumnName: "name", ColumnType: mapi.MDB_VARCHAR},
+		{ColumnName: "description", ColumnType: mapi.MDB_CLOB},
+	}
+	dest := make([]driver.Value, 2)
+	type tc struct {
+		row         string
+		name        string
+		description string
+	}
+	var tcs = []tc{
+		{"[ \"say \\\"hi\\\"\",\t\"it\\'s\"\t]", "say \"hi\"", "it's"},
+		{"[ \"back\\\\slash\",\t\"line\\none\\ttab\"\t]", "back\\slash", "line\none\ttab"},
+		{"[ \"a, b\",\t\"\\\"\\\"\"\t]", "a, b", "\"\""},
+	}
+	for _, c := range tcs {
+		if err := r.scanRow(c.row, dest); err != nil {
+			t.Errorf("Error scanning row %s: %v", c.row, err)
+			continue
+		}
+		var name, description string
+		if b, ok := dest[0].([]byte); ok {
+			name = string(b)
+		}
+		if b, ok := dest[1].([]byte); ok {
+			description = string(b)
+		}
+		if name != c.name || description != c.description {
+			t.Errorf("Unexpected values %q %q, expected: %q %q", name, description, c.name, c.description)
+		}
+	}
+}
+
+func TestRowsStoreResponse(t *testing.T) {
+	// The response to "select a, b from t; select c from u; insert into u ..."
+	resp, err := mapi.ParseResponse(`&1 2 1 2 1
+% sys.t,	sys.t # table_name
+% a,	b # name
+% int,	int # type
+% 1,	1 # length
+[ 1,	2	]
+&1 3 1 1 1
+% sys.u # table_name
+% c # name
+% varchar # type
+% 5 # length
+[ "hello"	]
+&2 5 -1
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := newRows(context.Background(), nil)
+	if err := r.storeResponse(resp); err != nil {
+		t.Fatal(err)
+	}
+	if r.queryId != 3 || r.rowCount != 1 || r.serverOpen {
+		t.Errorf("Unexpected metadata query %d, rows %d, open %v", r.queryId, r.rowCount, r.serverOpen)
+	}
+	if cols := r.Columns(); len(cols) != 1 || cols[0] != "c" {
+		t.Errorf("Unexpected columns %v", cols)
+	}
+	dest := make([]driver.Value, 1)
+	if err := r.Next(dest); err != nil {
+		t.Fatal(err)
+	}
+	if b, ok := dest[0].([]byte); !ok || string(b) != "hello" {
+		t.Errorf("Unexpected value %v", dest[0])
+	}
+}
+
+func TestRowsScanNull(t *testing.T) {
+	r := newRows(context.Background(), nil)
+	r.schema = []mapi.TableElement{
+		{ColumnName: "name", ColumnType: mapi.MDB_VARCHAR},
+		{ColumnName: "value", ColumnType: mapi.MDB_INT},
+		{ColumnName: "ts", ColumnType: mapi.MDB_TIMESTAMP},
+	}
+	dest := make([]driver.Value, 3)
+	if err := r.scanRow("[ NULL,\tNULL,\tNULL\t]", dest); err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range dest {
+		if v != nil {
+			t.Errorf("Unexpected value %v in column %d, expected: nil", v, i)
+		}
+	}
+
+	if err := r.scanRow("[ \"NULL\",\t1,\tNULL\t]", dest); err != nil {
+		t.Fatal(err)
+	}
+	if b, ok := dest[0].([]byte); !ok || string(b) != "NULL" {
+		t.Errorf("Unexpected value %v, expected: NULL text", dest[0])
+	}
+}
+
+func TestRowsScanDecimal(t *testing.T) {
+	r := newRows(context.Background(), nil)
+	r.schema = []mapi.TableElement{
+		{ColumnName: "amount", ColumnType: mapi.MDB_DECIMAL, Precision: 18, Scale: 4},
+	}
+	dest := make([]driver.Value, 1)
+	if err := r.scanRow("[ 12345678901234.5678\t]", dest); err != nil {
+		t.Fatal(err)
+	}
+	var d Decimal
+	if err := d.Scan(dest[0]); err != nil {
+		t.Fatal(err)
+	}
+	if d.String() != "12345678901234.5678" || d.Scale() != 4 {
+		t.Errorf("Invalid value: %s, expected: 12345678901234.5678", d)
+	}
+
+	// The sql package converts the text into a string or a float64
+	var s sql.NullString
+	if err := s.Scan(dest[0]); err != nil || s.String != "12345678901234.5678" {
+		t.Errorf("Invalid value: %v, %v", s, err)
+	}
+	var f sql.NullFloat64
+	if err := f.Scan(dest[0]); err != nil || f.Float64 != 12345678901234.5678 {
+		t.Errorf("Invalid value: %v, %v", f, err)
+	}
+	if st := r.ColumnTypeScanType(0); st != reflect.TypeOf("") {
+		t.Errorf("Unexpected scan type %v", st)
+	}
+}
+
+func TestRowsScanJSON(t *testing.T) {
+	r := newRows(context.Background(), nil)
+	r.schema = []mapi.TableElement{
+		{ColumnName: "payload", ColumnType: mapi.MDB_JSON},
+		{ColumnName: "other", ColumnType: mapi.MDB_JSON},
+	}
+	dest := make([]driver.Value, 2)
+	if err := r.scanRow("[ \"{\\\"name\\\": \\\"it's\\\"}\",\tNULL\t]", dest); err != nil {
+		t.Fatal(err)
+	}
+	var s sql.NullString
+	if err := s.Scan(dest[0]); err != nil || s.String != `{"name": "it's"}` {
+		t.Errorf("Invalid value: %v, %v", s, err)
+	}
+	if err := s.Scan(dest[1]); err != nil || s.Valid {
+		t.Errorf("Invalid value for NULL: %v, %v", s, err)
+	}
+	var v JSON[testEvent]
+	if err := v.Scan(dest[0]); err != nil || v.V.Name != "it's" {
+		t.Errorf("Invalid value: %+v, %v", v.V, err)
+	}
+	if st := r.ColumnTypeScanType(0); st != reflect.TypeOf("") {
+		t.Errorf("Unexpected scan type %v", st)
+	}
+}
+
+func TestRowsScanTemporal(t *testing.T) {
+	r := newRows(context.Background(), nil)
+	r.schema = []mapi.TableElement{
+		{ColumnName: "d", ColumnType: mapi.MDB_DATE},
+		{ColumnName: "tm", ColumnType: mapi.MDB_TIME},
+		{ColumnName: "ts", ColumnType: mapi.MDB_TIMESTAMP},
+	}
+	dest := make([]driver.Value, 3)
+	if err := r.scanRow("[ 2024-02-29,\t23:59:58.123456,\t2024-02-29 23:59:58.123456\t]", dest); err != nil {
+		t.Fatal(err)
+	}
+	// The scan type of a column is the type of its values
+	for i, v := range dest {
+		if st := r.ColumnTypeScanType(i); st != reflect.TypeOf(v) {
+			t.Errorf("Unexpected scan type %v for a value of type %T", st, v)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package monetdb
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/MonetDB/MonetDB-Go/v2/mapi"
+)
+
+// sessionStmt matches the statements that change the session settings
+// that are restored by ResetSession.
+var sessionStmt = regexp.MustCompile(`(?i)(^|;)\s*set\s+(schema|role|time\s+zone)\b`)
+
+// session tracks the state of the server session that a user of the
+// connection can leave behind. The state is restored before the connection
+// is handed to the next user of the connection pool.
+type session struct {
+	// tracking is enabled after the connection is configured, so the
+	// settings of the driver itself become part of the baseline
+	tracking bool
+
+	// The baseline is only retrieved from the server when a statement is
+	// executed that changes it
+	captured bool
+	schema   string
+	role     string
+	timezone int
+
+	changed bool
+	// used is set when a statement is executed after the last reset
+	used bool
+
+	// defaultAutoCommit is the autocommit setting of the session. When
+	// autocommit is disabled, the session is always in a transaction.
+	defaultAutoCommit bool
+	// The transaction state as reported by the server. Autocommit is
+	// disabled while a transaction that is started with START TRANSACTION
+	// is running.
+	autoCommit    bool
+	inTransaction bool
+
+	// Result sets and prepared statements that are closed by the driver,
+	// but could not be released on the server when they were closed
+	closedResults  []int
+	closedPrepared []int
+
+	// The query timeout in seconds that the connector configured, and the
+	// one that is currently set on the server. Zero disables the timeout.
+	baseTimeout  int
+	queryTimeout int
+	// contextTimeout is set when the deadline of a context is used as the
+	// query timeout
+	contextTimeout bool
+}
+
+// watch is called before a query is executed. When the query changes the
+// session settings, the baseline is retrieved first.
+func (s *session) watch(m *mapi.MapiConn, query string) error {
+	if !s.tracking {
+		return nil
+	}
+	s.used = true
+	if !sessionStmt.MatchString(query) {
+		return nil
+	}
+	if !s.captured {
+		if err := s.capture(m); err != nil {
+			return err
+		}
+	}
+	s.changed = true
+	return nil
+}
+
+// observe is called with every response of the server, to keep track of the
+// transaction state of the session.
+func (s *session) observe(resp *mapi.Response) {
+	if resp == nil {
+		return
+	}
+	for _, p := range resp.Parts {
+		if tc, ok := p.(*mapi.TransactionChange); ok {
+			s.autoCommit = tc.AutoCommit
+			s.inTransaction = !tc.AutoCommit
+		}
+	}
+}
+
+func (s *session) capture(m *mapi.MapiConn) error {
+	resp, err := m.Execute("SELECT CURRENT_SCHEMA, CURRENT_ROLE, CURRENT_TIMEZONE")
+	if err != nil {
+		return err
+	}
+	var r mapi.ResultSet
+	if err := r.StoreResponse(resp); err != nil {
+		return err
+	}
+	if len(r.Rows) != 1 || len(r.Rows[0]) != 3 {
+		return fmt.Errorf("monetdb: unexpected session settings")
+	}
+	row := r.Rows[0]
+	s.schema, _ = row[0].(string)
+	s.role, _ = row[1].(string)
+	s.timezone, err = intervalSeconds(row[2])
+	if err != nil {
+		return err
+	}
+	s.captured = true
+	return nil
+}
+
+// intervalSeconds returns the number of seconds of a sec_interval value.
+func intervalSeconds(v mapi.Value) (int, error) {
+	switch val := v.(type) {
+	case time.Duration:
+		return int(val / time.Second), nil
+	default:
+		return 0, fmt.Errorf("monetdb: unexpected interval value %v", v)
+	}
+}
+
+// timeout returns the query timeout in seconds for a statement that is
+// executed with the context. A deadline of the context can only make the
+// timeout shorter.
+func (s *session) timeout(ctx context.Context) (int, error) {
+	if !s.contextTimeout {
+		return s.baseTimeout, nil
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return s.baseTimeout, nil
+	}
+	remaining := time.Until(deadline)
+	if remaining <= 0 {
+		return 0, context.DeadlineExceeded
+	}
+	secs := timeoutSeconds(remaining)
+	if s.baseTimeout == 0 || secs < s.baseTimeout {
+		return secs, nil
+	}
+	return s.baseTimeout, nil
+}
+
+// setQueryTimeout changes the query timeout of the session, when it is not
+// already set to the number of seconds.
+func (s *session) setQueryTimeout(m *mapi.MapiConn, secs int) error {
+	if secs == s.queryTimeout {
+		return nil
+	}
+	if _, err := m.Execute(fmt.Sprintf("CALL sys.setquerytimeout(%d)", secs)); err != nil {
+		return err
+	}
+	s.queryTimeout = secs
+	return nil
+}
+
+// timeoutSeconds rounds the duration up to whole seconds, the unit of the
+// query timeout of the server.
+func timeoutSeconds(d time.Duration) int {
+	secs := int(d / time.Second)
+	if d%time.Second != 0 {
+		secs++
+	}
+	return secs
+}
+
+// closeResult is called when a result set that is still open on the server
+// could not be released. It is released when the session is reset.
+func (s *session) closeResult(queryId int) {
+	s.closedResults = append(s.closedResults, queryId)
+}
+
+// closePrepared is called when a prepared statement could not be released.
+// It is released when the session is reset.
+func (s *session) closePrepared(execId int) {
+	s.closedPrepared = append(s.closedPrepared, execId)
+}
+
+// reset restores the baseline of the session. Only the settings that were
+// changed are restored, so in most cases no command is sent to the server.
+func (s *session) reset(ctx context.Context, m *mapi.MapiConn) error {
+	var queries []string
+	if s.inTransaction && s.used {
+		queries = append(queries, "ROLLBACK")
+	}
+	if s.changed {
+		queries = append(queries, fmt.Sprintf("SET SCHEMA %s", quoteIdentifier(s.schema)))
+		if s.role != "" {
+			queries = append(queries, fmt.Sprintf("SET ROLE %s", quoteIdentifier(s.role)))
+		}
+		queries = append(queries, timezoneQuery(s.timezone))
+	}
+
+	for _, q := range queries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		resp, err := m.Execute(q)
+		s.observe(resp)
+		if err != nil {
+			return err
+		}
+	}
+	if err := s.setQueryTimeout(m, s.baseTimeout); err != nil {
+		return err
+	}
+	s.changed = false
+	s.used = false
+
+	// The server may already have released a result set or prepared
+	// statement, so errors reported by the server are ignored
+	for len(s.closedResults) > 0 {
+		if _, err := m.CloseResult(s.closedResults[0]); err != nil && !isServerError(err) {
+			return err
+		}
+		s.closedResults = s.closedResults[1:]
+	}
+	for len(s.closedPrepared) > 0 {
+		if _, err := m.ReleasePrepared(s.closedPrepared[0]); err != nil && !isServerError(err) {
+			return err
+		}
+		s.closedPrepared = s.closedPrepared[1:]
+	}
+	return nil
+}
+
+// closeResult releases a result set that is still open on the server. The
+// sql package holds the lock of the connection when a result set is closed,
+// so the command is sent right away. Otherwise the result sets of a
+// long-lived connection would pile up until the session is reset.
+func (c *Conn) closeResult(queryId int) {
+	if c.IsValid() {
+		_, err := c.mapi.CloseResult(queryId)
+		// The server may already have released the result set
+		if err == nil || isServerError(err) {
+			return
+		}
+		c.checkErr(err)
+	}
+	c.session.closeResult(queryId)
+}
+
+// closePrepared releases a prepared statement on the server, like
+// closeResult.
+func (c *Conn) closePrepared(execId int) {
+	if c.IsValid() {
+		_, err := c.mapi.ReleasePrepared(execId)
+		if err == nil || isServerError(err) {
+			return
+		}
+		c.checkErr(err)
+	}
+	c.session.closePrepared(execId)
+}
+
+func isServerError(err error) bool {
+	var e *Error
+	return errors.As(err, &e)
+}
+
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// ResetSession is called by the sql package before a connection from the
+// pool is reused. Open transactions are rolled back, the schema, role, time
+// zone and query timeout are restored and result sets and prepared statements that
+// could not be released when the previous user closed them are released on the
+// server.
+func (c *Conn) ResetSession(ctx context.Context) error {
+	if !c.IsValid() {
+		return driver.ErrBadConn
+	}
+	if err := c.session.reset(ctx, c.mapi); err != nil {
+		// The state of the session is unknown, so the connection can not
+		// be reused
+		c.bad = true
+		return driver.ErrBadConn
+	}
+	return nil
+}
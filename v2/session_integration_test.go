@@ -0,0 +1,144 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package monetdb
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+)
+
+func TestSessionResetIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	db, err := sql.Open("monetdb", "monetdb:monetdb@localhost:50000/monetdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
+
+	ctx := context.Background()
+
+	t.Run("Schema is restored when the connection is reused", func(t *testing.T) {
+		if _, err := db.Exec("create schema if not exists session_test"); err != nil {
+			t.Fatal(err)
+		}
+		var schema string
+		if err := db.QueryRow("select current_schema").Scan(&schema); err != nil {
+			t.Fatal(err)
+		}
+
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := conn.ExecContext(ctx, "set schema session_test"); err != nil {
+			t.Fatal(err)
+		}
+		conn.Close()
+
+		var current string
+		if err := db.QueryRow("select current_schema").Scan(&current); err != nil {
+			t.Fatal(err)
+		}
+		if current != schema {
+			t.Errorf("Unexpected schema %s, expected: %s", current, schema)
+		}
+	})
+
+	t.Run("Open transaction is rolled back when the connection is reused", func(t *testing.T) {
+		if _, err := db.Exec("create table if not exists session_test1 (id int)"); err != nil {
+			t.Fatal(err)
+		}
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := conn.ExecContext(ctx, "start transaction"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := conn.ExecContext(ctx, "insert into session_test1 values (1)"); err != nil {
+			t.Fatal(err)
+		}
+		conn.Close()
+
+		var count int
+		if err := db.QueryRow("select count(*) from session_test1").Scan(&count); err != nil {
+			t.Fatal(err)
+		}
+		if count != 0 {
+			t.Errorf("Unexpected number of rows %d", count)
+		}
+	})
+
+	t.Run("Prepared statements are released when they are closed", func(t *testing.T) {
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		count := func() int {
+			var n int
+			if err := conn.QueryRowContext(ctx, "select count(*) from sys.prepared_statements").Scan(&n); err != nil {
+				t.Skip("prepared statements are not listed by the server")
+			}
+			return n
+		}
+		before := count()
+		for i := 0; i < 10; i++ {
+			stmt, err := conn.PrepareContext(ctx, "select ? + 1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			var n int
+			if err := stmt.QueryRowContext(ctx, i).Scan(&n); err != nil {
+				t.Fatal(err)
+			}
+			stmt.Close()
+		}
+		// The connection is not reset, so the statements were released
+		// when they were closed
+		if after := count(); after != before {
+			t.Errorf("Unexpected number of prepared statements %d, expected: %d", after, before)
+		}
+	})
+
+	t.Run("Large results are released when they are closed", func(t *testing.T) {
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		for i := 0; i < 10; i++ {
+			rows, err := conn.QueryContext(ctx, "select value from sys.generate_series(0, 1000)")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !rows.Next() {
+				t.Fatal(rows.Err())
+			}
+			if err := rows.Close(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		var n int
+		if err := conn.QueryRowContext(ctx, "select 1").Scan(&n); err != nil || n != 1 {
+			t.Errorf("Unexpected result %d, %v", n, err)
+		}
+	})
+
+	t.Run("Cleanup", func(t *testing.T) {
+		if _, err := db.Exec("drop table session_test1"); err != nil {
+			t.Error(err)
+		}
+		if _, err := db.Exec("drop schema session_test"); err != nil {
+			t.Error(err)
+		}
+	})
+}
@@ -0,0 +1,141 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package monetdb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/MonetDB/MonetDB-Go/v2/mapi"
+)
+
+func TestSessionStmt(t *testing.T) {
+	type tc struct {
+		q string
+		e bool
+	}
+	var tcs = []tc{
+		{"SET SCHEMA test", true},
+		{"  set role sysadmin", true},
+		{"set time zone interval '+02:00' hour to minute", true},
+		{"select 1; SET SCHEMA test", true},
+		{"SET optimizer = 'minimal_pipe'", false},
+		{"select * from settings", false},
+		{"update t set schema = 'x'", false},
+	}
+
+	for _, c := range tcs {
+		if sessionStmt.MatchString(c.q) != c.e {
+			t.Errorf("Unexpected match for %s, expected: %v", c.q, c.e)
+		}
+	}
+}
+
+func TestTimezoneQuery(t *testing.T) {
+	type tc struct {
+		offset int
+		e      string
+	}
+	var tcs = []tc{
+		{0, "SET TIME ZONE INTERVAL '+00:00' HOUR TO MINUTE;"},
+		{3600, "SET TIME ZONE INTERVAL '+01:00' HOUR TO MINUTE;"},
+		{19800, "SET TIME ZONE INTERVAL '+05:30' HOUR TO MINUTE;"},
+		{-18000, "SET TIME ZONE INTERVAL '-05:00' HOUR TO MINUTE;"},
+		{-1800, "SET TIME ZONE INTERVAL '-00:30' HOUR TO MINUTE;"},
+	}
+
+	for _, c := range tcs {
+		if q := timezoneQuery(c.offset); q != c.e {
+			t.Errorf("Invalid query: %s, expected: %s", q, c.e)
+		}
+	}
+}
+
+func TestSessionObserve(t *testing.T) {
+	var s session
+	resp, _ := mapi.ParseResponse("&4 f\n")
+	s.observe(resp)
+	if !s.inTransaction || s.autoCommit {
+		t.Error("Session is not in a transaction after START TRANSACTION")
+	}
+	resp, _ = mapi.ParseResponse("&4 t\n")
+	s.observe(resp)
+	if s.inTransaction || !s.autoCommit {
+		t.Error("Session is in a transaction after COMMIT")
+	}
+}
+
+func TestSessionTimeout(t *testing.T) {
+	type tc struct {
+		d time.Duration
+		e int
+	}
+	var tcs = []tc{
+		{time.Second, 1},
+		{1500 * time.Millisecond, 2},
+		{time.Millisecond, 1},
+		{time.Minute, 60},
+	}
+
+	for _, c := range tcs {
+		if v := timeoutSeconds(c.d); v != c.e {
+			t.Errorf("Invalid value: %d, expected: %d", v, c.e)
+		}
+	}
+
+	t.Run("Verify the deadline is ignored when disabled", func(t *testing.T) {
+		s := session{baseTimeout: 30}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if v, _ := s.timeout(ctx); v != 30 {
+			t.Errorf("Invalid value: %d, expected: 30", v)
+		}
+	})
+
+	t.Run("Verify the deadline shortens the timeout", func(t *testing.T) {
+		s := session{baseTimeout: 30, contextTimeout: true}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if v, _ := s.timeout(ctx); v != 5 {
+			t.Errorf("Invalid value: %d, expected: 5", v)
+		}
+		if v, _ := s.timeout(context.Background()); v != 30 {
+			t.Errorf("Invalid value: %d, expected: 30", v)
+		}
+	})
+
+	t.Run("Verify the deadline does not extend the timeout", func(t *testing.T) {
+		s := session{baseTimeout: 30, contextTimeout: true}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+		defer cancel()
+		if v, _ := s.timeout(ctx); v != 30 {
+			t.Errorf("Invalid value: %d, expected: 30", v)
+		}
+	})
+
+	t.Run("Verify an exceeded deadline", func(t *testing.T) {
+		s := session{contextTimeout: true}
+		ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+		defer cancel()
+		if _, err := s.timeout(ctx); err != context.DeadlineExceeded {
+			t.Errorf("Unexpected error %v", err)
+		}
+	})
+}
+
+func TestIntervalSeconds(t *testing.T) {
+	v, err := mapi.ConvertValue("-5400.000", mapi.MDB_SEC_INTERVAL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secs, err := intervalSeconds(v)
+	if err != nil || secs != -5400 {
+		t.Errorf("Invalid value: %d, %v, expected: -5400", secs, err)
+	}
+	if _, err := intervalSeconds("3600.000"); err == nil {
+		t.Error("Unexpected interval value did not fail")
+	}
+}
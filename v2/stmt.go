@@ -13,8 +13,15 @@ import (
 
 type Stmt struct {
 	isPreparedStatement bool
+	// control is set for the statements that the driver executes itself,
+	// for example to end a transaction. These are also executed when the
+	// transaction is aborted.
+	control bool
 	conn  *Conn
 	query mapi.Query
+	// resultset holds the state of the prepared statement. Every execution
+	// stores its result in a new resultset, so the results of earlier
+	// executions that are still open are not overwritten.
 	resultset mapi.ResultSet
 }
 
@@ -30,14 +37,25 @@ func newStmt(c *Conn, q string, prepare bool) *Stmt {
 }
 
 func executeStmt(c *Conn, query string) error {
+	return executeStmtContext(context.Background(), c, query)
+}
+
+// executeStmtContext executes a control statement of the driver. When the
+// context is done before the server replies, the statement is interrupted
+// and the connection can not be used anymore.
+func executeStmtContext(ctx context.Context, c *Conn, query string) error {
 	stmt := newStmt(c, query, false)
-	_, err := stmt.Exec(nil)
+	stmt.control = true
+	_, err := stmt.ExecContext(ctx, nil)
 	defer stmt.Close()
 	return err
 }
 
 func (s *Stmt) Close() error {
 	// TODO: check if this is correct, the pool should handle the connections
+	if s.conn != nil && s.isPreparedStatement && s.resultset.Metadata.ExecId != -1 {
+		s.conn.closePrepared(s.resultset.Metadata.ExecId)
+	}
 	s.conn = nil
 	return nil
 }
@@ -57,25 +75,68 @@ func (s *Stmt) Exec(args []driver.Value) (driver.Result, error) {
 // the command when the context is cancelled. At this point in time MonetDB does not support cancelling
 // a running query. This feature is planned for the next release. When that comes available, we will add
 // a function call that cancels the query when a timeout occurs before it is finished.
-func (s *Stmt) mapiDo(ctx context.Context, args []driver.NamedValue) (string, error) {
+func (s *Stmt) mapiDo(ctx context.Context, args []driver.NamedValue) (*mapi.Response, error) {
+	if s.conn == nil || !s.conn.IsValid() {
+		return nil, driver.ErrBadConn
+	}
+	tx := s.conn.tx
+	if tx != nil && tx.err != nil && !s.control {
+		return nil, &txAbortedError{cause: tx.err}
+	}
+	// A control statement, like COMMIT, must not use the shortened timeout
+	// of the context of an earlier statement
+	timeout := s.conn.session.baseTimeout
+	if !s.control {
+		t, err := s.conn.session.timeout(ctx)
+		if err != nil {
+			return nil, err
+		}
+		timeout = t
+	}
 	type res struct {
-		resultstring string;
+		response *mapi.Response;
 		err error
 	}
 	c := make(chan res, 1)
 
     go func() {
+		timeoutErr := s.conn.session.setQueryTimeout(s.conn.mapi, timeout)
+		// In an aborted transaction the server rejects every statement but
+		// ROLLBACK, also the call that changes the timeout. A control
+		// statement is executed anyway, so the transaction can be ended.
+		if timeoutErr != nil && !(s.control && isServerError(timeoutErr)) {
+			c <- res{nil, timeoutErr}
+			return
+		}
 		r, err := s.exec(args)
+		s.conn.session.observe(r)
+		if timeoutErr != nil && err == nil {
+			// The timeout is restored after the transaction has ended.
+			// When that fails, the next statement changes it again.
+			s.conn.session.setQueryTimeout(s.conn.mapi, timeout)
+		}
 		result := res{r, err}
 		c <- result
 		}()
 
     select {
     case <-ctx.Done():
-        <-c // Wait for the goroutine to return. Later we need to cancel the query on the database
-        return "", ctx.Err()
+        if s.control {
+            // A control statement must not block when the server does not respond
+            s.conn.mapi.Interrupt()
+        }
+        result := <-c // Wait for the goroutine to return. Later we need to cancel the query on the database
+        if tx != nil && !s.control && isServerError(result.err) {
+            tx.err = result.err
+        }
+        s.conn.checkErr(result.err)
+        return nil, ctx.Err()
     case result := <-c:
-        return result.resultstring, result.err
+        if tx != nil && !s.control && isServerError(result.err) {
+            // The server aborts the transaction when a statement fails
+            tx.err = result.err
+        }
+        return result.response, s.conn.checkErr(result.err)
     }
 }
 
@@ -87,25 +148,15 @@ func (s *Stmt) execResult(ctx context.Context, args []driver.NamedValue) (driver
 		return res, res.err
 	}
 
-	err = s.resultset.StoreResult(r)
-	res.lastInsertId = s.resultset.Metadata.LastRowId
-	res.rowsAffected = s.resultset.Metadata.RowCount
+	var resultset mapi.ResultSet
+	err = resultset.StoreMetadata(r)
+	res.lastInsertId = resultset.Metadata.LastRowId
+	res.rowsAffected = resultset.Metadata.RowCount
 	res.err = err
 
 	return res, res.err
 }
 
-func convertRows(rows [][]mapi.Value, columncount int)([][]driver.Value) {
-	res := make([][]driver.Value, len(rows))
-	for i, row := range rows {
-		res[i] = make([]driver.Value, columncount)
-		for j, col := range row {
-			res[i][j] = col
-		}
-	}
-	return res
-}
-
 func convertParamValues(args []driver.Value)([]mapi.Value) {
 	res := make([]mapi.Value, len(args))
 	for i, arg := range args {
@@ -154,35 +205,25 @@ func (s *Stmt) Query(args []driver.Value) (driver.Rows, error) {
 }
 
 func (s *Stmt) queryResult(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
-	rows := newRows(s.conn.mapi, &s.resultset)
+	rows := newRows(ctx, s.conn)
 	r, err := s.mapiDo(ctx, args)
 	if err != nil {
 		rows.err = err
 		return rows, rows.err
 	}
 
-	err = s.resultset.StoreResult(r)
-	if err != nil {
-		rows.err = err
-		return rows, rows.err
-	}
-	// We have gotten the first batch of the resultset. The RowCount is the total number of rows in the result.
-	// But we have only at most mapi.MAPI_ARRAY_SIZE rows available.
-	rows.queryId = s.resultset.Metadata.QueryId
-	rows.lastRowId = s.resultset.Metadata.LastRowId
-	rows.rowCount = s.resultset.Metadata.RowCount
-	rows.offset = s.resultset.Metadata.Offset
-	rows.rows = convertRows(s.resultset.Rows, s.resultset.Metadata.ColumnCount)
-	rows.schema = s.resultset.Schema
-
+	rows.err = rows.storeResponse(r)
 	return rows, rows.err
 }
 
-func (s *Stmt) exec(args []driver.NamedValue) (string, error) {
+func (s *Stmt) exec(args []driver.NamedValue) (*mapi.Response, error) {
+	if err := s.conn.session.watch(s.conn.mapi, s.query.SqlQuery); err != nil {
+		return nil, err
+	}
 	if s.isPreparedStatement && s.resultset.Metadata.ExecId == -1 {
 		err := s.query.PrepareQuery(&s.resultset)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 	}
 
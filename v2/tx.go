@@ -4,32 +4,234 @@
 
 package monetdb
 
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// rollbackTimeout limits the time of the rollback of a transaction whose
+// context is done. The sql package rolls back a transaction when its context
+// is cancelled, the rollback must not block when the server does not respond.
+const rollbackTimeout = 5 * time.Second
+
+// ErrTxAborted is reported when a statement is executed in a transaction
+// after an earlier statement of that transaction failed. The server aborts
+// the transaction when a statement fails, the only way forward is to roll
+// back the transaction. Use errors.Is to check for this error, the error
+// also wraps the error of the statement that failed.
+var ErrTxAborted = errors.New("monetdb: transaction is aborted")
+
+type txAbortedError struct {
+	cause error
+}
+
+func (e *txAbortedError) Error() string {
+	return fmt.Sprintf("monetdb: transaction is aborted because of an earlier error: %v", e.cause)
+}
+
+func (e *txAbortedError) Unwrap() error {
+	return e.cause
+}
+
+func (e *txAbortedError) Is(target error) bool {
+	return target == ErrTxAborted
+}
+
 type Tx struct {
+	ctx  context.Context
 	conn *Conn
+	// err is set when a statement of the transaction failed, the
+	// transaction can then only be rolled back
 	err  error
+	// savepoints contains the names of the savepoints of the transaction,
+	// in the order in which they were created
+	savepoints []string
 }
 
-func newTx(c *Conn) *Tx {
+func newTx(ctx context.Context, c *Conn) *Tx {
 	return &Tx{
+		ctx:  ctx,
 		conn: c,
 		err:  nil,
 	}
 }
 
+// Commit commits the transaction, using the context that was passed to
+// BeginTx.
 func (t *Tx) Commit() error {
-	err := executeStmt(t.conn, "COMMIT")
+	return t.commit(t.ctx)
+}
+
+// Rollback rolls back the transaction, using the context that was passed to
+// BeginTx. When that context is already done, for example because the sql
+// package rolls back the transaction after the context was cancelled, the
+// rollback gets a new context with a short timeout.
+func (t *Tx) Rollback() error {
+	ctx := t.ctx
+	if ctx.Err() != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), rollbackTimeout)
+		defer cancel()
+	}
+	return t.rollback(ctx)
+}
+
+func (t *Tx) commit(ctx context.Context) error {
+	if err := t.checkState(); err != nil {
+		return err
+	}
+	if t.err != nil {
+		// The COMMIT would fail, the transaction is rolled back instead
+		aborted := &txAbortedError{cause: t.err}
+		executeStmtContext(ctx, t.conn, "ROLLBACK")
+		t.end()
+		return aborted
+	}
+	err := executeStmtContext(ctx, t.conn, "COMMIT")
 	if err != nil {
 		t.err = err
 	}
+	t.end()
 
 	return err
 }
 
-func (t *Tx) Rollback() error {
-	err := executeStmt(t.conn, "ROLLBACK")
+func (t *Tx) rollback(ctx context.Context) error {
+	if err := t.checkState(); err != nil {
+		return err
+	}
+	err := executeStmtContext(ctx, t.conn, "ROLLBACK")
 	if err != nil {
 		t.err = err
 	}
+	t.end()
 
 	return err
 }
+
+// checkState verifies that the server is still in the transaction. The
+// transaction can be ended without the driver noticing, for example when a
+// COMMIT statement is executed as a query.
+func (t *Tx) checkState() error {
+	if t.conn.tx != t || !t.conn.session.inTransaction {
+		t.end()
+		return fmt.Errorf("monetdb: transaction was already ended on the server")
+	}
+	return nil
+}
+
+func (t *Tx) end() {
+	t.savepoints = nil
+	if t.conn.tx == t {
+		t.conn.tx = nil
+	}
+}
+
+var savepointName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func validSavepoint(name string) error {
+	if !savepointName.MatchString(name) {
+		return fmt.Errorf("monetdb: invalid savepoint name: %q", name)
+	}
+	return nil
+}
+
+// normalizeSavepoint returns the name of the savepoint as the server stores
+// it. The names are unquoted identifiers, which the server converts to
+// lower case.
+func normalizeSavepoint(name string) (string, error) {
+	if err := validSavepoint(name); err != nil {
+		return "", err
+	}
+	return strings.ToLower(name), nil
+}
+
+// savepointIndex returns the position of the savepoint, or -1 when the
+// transaction does not have a savepoint with that name.
+func (t *Tx) savepointIndex(name string) int {
+	for i, n := range t.savepoints {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Savepoint creates a savepoint with the given name in the transaction. The
+// name must be a valid identifier that is not used by another savepoint of
+// the transaction. Like other identifiers, the name is not case sensitive.
+func (t *Tx) Savepoint(name string) error {
+	name, err := normalizeSavepoint(name)
+	if err != nil {
+		return err
+	}
+	if t.savepointIndex(name) != -1 {
+		return fmt.Errorf("monetdb: savepoint already exists: %s", name)
+	}
+	if t.err != nil {
+		return &txAbortedError{cause: t.err}
+	}
+	if err := executeStmtContext(t.ctx, t.conn, fmt.Sprintf("SAVEPOINT %s", name)); err != nil {
+		return err
+	}
+	t.savepoints = append(t.savepoints, name)
+	return nil
+}
+
+// RollbackTo undoes the changes of the transaction that were made after
+// the savepoint was created. The savepoints that were created after this
+// savepoint are removed, the savepoint itself can be used again.
+func (t *Tx) RollbackTo(name string) error {
+	i, err := t.lookupSavepoint(name)
+	if err != nil {
+		return err
+	}
+	if err := executeStmtContext(t.ctx, t.conn, fmt.Sprintf("ROLLBACK TO SAVEPOINT %s", name)); err != nil {
+		return err
+	}
+	t.savepoints = t.savepoints[:i+1]
+	// The server accepted the rollback, so the transaction can be used again
+	t.err = nil
+	return nil
+}
+
+// Release removes the savepoint and the savepoints that were created after
+// it. The changes that were made after the savepoint are kept.
+func (t *Tx) Release(name string) error {
+	i, err := t.lookupSavepoint(name)
+	if err != nil {
+		return err
+	}
+	if t.err != nil {
+		return &txAbortedError{cause: t.err}
+	}
+	if err := executeStmtContext(t.ctx, t.conn, fmt.Sprintf("RELEASE SAVEPOINT %s", name)); err != nil {
+		return err
+	}
+	t.savepoints = t.savepoints[:i]
+	return nil
+}
+
+func (t *Tx) lookupSavepoint(name string) (int, error) {
+	name, err := normalizeSavepoint(name)
+	if err != nil {
+		return -1, err
+	}
+	i := t.savepointIndex(name)
+	if i == -1 {
+		return -1, fmt.Errorf("monetdb: unknown savepoint: %s", name)
+	}
+	return i, nil
+}
+
+// Savepoints returns the names of the savepoints of the transaction, in the
+// order in which they were created. The names are in lower case.
+func (t *Tx) Savepoints() []string {
+	res := make([]string, len(t.savepoints))
+	copy(res, t.savepoints)
+	return res
+}
@@ -7,6 +7,7 @@
  import (
 	"context"
 	"database/sql"
+	"errors"
 	"strings"
 	 "testing"
  )
@@ -240,3 +241,280 @@ func TestTxSerializableIntegration(t *testing.T) {
 		}
 	})
 }
+
+func TestTxSavepointIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	db, err := sql.Open("monetdb", "monetdb:monetdb@localhost:50000/monetdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	savepoint := func(f func(c *Conn) error) error {
+		return conn.Raw(func(driverConn any) error {
+			return f(driverConn.(*Conn))
+		})
+	}
+
+	t.Run("Exec create table", func(t *testing.T) {
+		_, err := conn.ExecContext(ctx, "create table test5 ( id int )")
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Savepoint without transaction should fail", func(t *testing.T) {
+		err := savepoint(func(c *Conn) error { return c.Savepoint("sp1") })
+		if err == nil {
+			t.Error("Savepoint did not fail as expected")
+		}
+	})
+
+	t.Run("Rollback to savepoint", func(t *testing.T) {
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tx.Exec("insert into test5 values ( 1 )"); err != nil {
+			t.Fatal(err)
+		}
+		if err := savepoint(func(c *Conn) error { return c.Savepoint("sp1") }); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tx.Exec("insert into test5 values ( 2 )"); err != nil {
+			t.Fatal(err)
+		}
+		if err := savepoint(func(c *Conn) error { return c.Savepoint("sp2") }); err != nil {
+			t.Fatal(err)
+		}
+		if err := savepoint(func(c *Conn) error { return c.RollbackTo("sp1") }); err != nil {
+			t.Fatal(err)
+		}
+		if err := savepoint(func(c *Conn) error { return c.Release("sp2") }); err == nil {
+			t.Error("Release of removed savepoint did not fail as expected")
+		}
+		if err := savepoint(func(c *Conn) error { return c.Release("sp1") }); err != nil {
+			t.Error(err)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatal(err)
+		}
+
+		var count int
+		if err := conn.QueryRowContext(ctx, "select count(*) from test5").Scan(&count); err != nil {
+			t.Fatal(err)
+		}
+		if count != 1 {
+			t.Errorf("Unexpected number of rows %d", count)
+		}
+	})
+
+	t.Run("Savepoint names are not case sensitive", func(t *testing.T) {
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer tx.Rollback()
+		if err := savepoint(func(c *Conn) error { return c.Savepoint("a") }); err != nil {
+			t.Fatal(err)
+		}
+		if err := savepoint(func(c *Conn) error { return c.RollbackTo("A") }); err != nil {
+			t.Error(err)
+		}
+		if err := savepoint(func(c *Conn) error { return c.Release("A") }); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("Invalid savepoint name should fail", func(t *testing.T) {
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer tx.Rollback()
+		if err := savepoint(func(c *Conn) error { return c.Savepoint("sp1; drop table test5") }); err == nil {
+			t.Error("Savepoint did not fail as expected")
+		}
+	})
+
+	t.Run("Exec drop table", func(t *testing.T) {
+		_, err := conn.ExecContext(ctx, "drop table test5")
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestTxAutoCommitIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	db, err := sql.Open("monetdb", "monetdb:monetdb@localhost:50000/monetdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	status := func(conn *sql.Conn) ConnStatus {
+		var s ConnStatus
+		conn.Raw(func(driverConn any) error {
+			s = driverConn.(*Conn).Status()
+			return nil
+		})
+		return s
+	}
+
+	t.Run("Commit after COMMIT statement should fail", func(t *testing.T) {
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s := status(conn); !s.InTransaction || s.AutoCommit {
+			t.Errorf("Unexpected status %+v", s)
+		}
+		if _, err := tx.Exec("commit"); err != nil {
+			t.Fatal(err)
+		}
+		if s := status(conn); s.InTransaction || !s.AutoCommit {
+			t.Errorf("Unexpected status %+v", s)
+		}
+		if err := tx.Commit(); err == nil {
+			t.Error("Commit did not fail as expected")
+		}
+	})
+
+	t.Run("Transaction without autocommit", func(t *testing.T) {
+		db, err := sql.Open("monetdb", "monetdb:monetdb@localhost:50000/monetdb?autocommit=false")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		if s := status(conn); s.AutoCommit || !s.InTransaction {
+			t.Errorf("Unexpected status %+v", s)
+		}
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tx.Exec("create table test6 ( id int )"); err != nil {
+			t.Fatal(err)
+		}
+		if err := tx.Rollback(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := conn.ExecContext(ctx, "select * from test6"); err == nil {
+			t.Error("Table was created after rollback")
+		}
+	})
+}
+
+func TestTxAbortedIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	db, err := sql.Open("monetdb", "monetdb:monetdb@localhost:50000/monetdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	t.Run("Statements fail after an error in the transaction", func(t *testing.T) {
+		tx, err := db.Begin()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tx.Exec("select * from no_such_table"); err == nil {
+			t.Fatal("query did not fail as expected")
+		}
+		_, err = tx.Exec("select 1")
+		if !errors.Is(err, ErrTxAborted) {
+			t.Errorf("Unexpected error %v", err)
+		}
+		if !IsUndefinedObject(err) {
+			t.Errorf("Error does not wrap the cause %v", err)
+		}
+		if err := tx.Commit(); !errors.Is(err, ErrTxAborted) {
+			t.Errorf("Unexpected error from commit %v", err)
+		}
+	})
+}
+
+func TestTxContextIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	db, err := sql.Open("monetdb", "monetdb:monetdb@localhost:50000/monetdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	t.Run("Exec create table", func(t *testing.T) {
+		_, err := db.Exec("create table test7 ( id int )")
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Cancelled context rolls back the transaction", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tx.Exec("insert into test7 values ( 1 )"); err != nil {
+			t.Fatal(err)
+		}
+		cancel()
+		if err := tx.Commit(); err == nil {
+			t.Error("Commit did not fail as expected")
+		}
+
+		var count int
+		if err := db.QueryRow("select count(*) from test7").Scan(&count); err != nil {
+			t.Fatal(err)
+		}
+		if count != 0 {
+			t.Errorf("Unexpected number of rows %d", count)
+		}
+	})
+
+	t.Run("BeginTx with expired context should fail", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := db.BeginTx(ctx, nil); err == nil {
+			t.Error("BeginTx did not fail as expected")
+		}
+	})
+
+	t.Run("Exec drop table", func(t *testing.T) {
+		_, err := db.Exec("drop table test7")
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,133 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package monetdb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/MonetDB/MonetDB-Go/v2/mapi"
+)
+
+func TestValidSavepoint(t *testing.T) {
+	type tc struct {
+		name string
+		ok   bool
+	}
+	var tcs = []tc{
+		{"sp1", true},
+		{"_batch_10", true},
+		{"Batch", true},
+		{"", false},
+		{"1sp", false},
+		{"sp 1", false},
+		{"sp1; drop table x", false},
+		{"\"sp1\"", false},
+	}
+
+	for _, c := range tcs {
+		err := validSavepoint(c.name)
+		if (err == nil) != c.ok {
+			t.Errorf("Unexpected result for %q: %v", c.name, err)
+		}
+	}
+}
+
+func TestSavepointLookup(t *testing.T) {
+	tx := &Tx{savepoints: []string{"sp1", "sp2"}}
+	if i, err := tx.lookupSavepoint("sp2"); err != nil || i != 1 {
+		t.Errorf("Unexpected result %d, %v", i, err)
+	}
+	if _, err := tx.lookupSavepoint("sp3"); err == nil {
+		t.Error("Lookup of unknown savepoint did not fail")
+	}
+	if err := tx.Savepoint("sp1"); err == nil {
+		t.Error("Duplicate savepoint did not fail")
+	}
+	// Unquoted identifiers are not case sensitive
+	if i, err := tx.lookupSavepoint("SP2"); err != nil || i != 1 {
+		t.Errorf("Unexpected result %d, %v", i, err)
+	}
+	if err := tx.Savepoint("Sp1"); err == nil {
+		t.Error("Duplicate savepoint with other case did not fail")
+	}
+}
+
+func TestTxState(t *testing.T) {
+	t.Run("Verify BeginTx fails when the session is in a transaction", func(t *testing.T) {
+		c := &Conn{session: session{defaultAutoCommit: true, autoCommit: false, inTransaction: true}}
+		if _, err := c.BeginTx(context.Background(), driver.TxOptions{}); err == nil {
+			t.Error("BeginTx did not fail as expected")
+		}
+	})
+
+	t.Run("Verify BeginTx without autocommit", func(t *testing.T) {
+		c := &Conn{session: session{defaultAutoCommit: false, autoCommit: false, inTransaction: true}}
+		opts := driver.TxOptions{Isolation: driver.IsolationLevel(sql.LevelSerializable)}
+		if _, err := c.BeginTx(context.Background(), opts); err == nil {
+			t.Error("BeginTx with isolation level did not fail as expected")
+		}
+		tx, err := c.BeginTx(context.Background(), driver.TxOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.tx != tx {
+			t.Error("Transaction is not active")
+		}
+	})
+
+	t.Run("Verify Commit fails when the transaction was ended on the server", func(t *testing.T) {
+		c := &Conn{session: session{defaultAutoCommit: true, autoCommit: true, inTransaction: false}}
+		tx := newTx(context.Background(), c)
+		c.tx = tx
+		if err := tx.Commit(); err == nil {
+			t.Error("Commit did not fail as expected")
+		}
+		if c.tx != nil {
+			t.Error("Transaction is still active")
+		}
+	})
+}
+
+func TestTxAborted(t *testing.T) {
+	cause := serverError(t, "!42S02!SELECT: no such table 'x'")
+	c := &Conn{
+		mapi:    &mapi.MapiConn{},
+		session: session{defaultAutoCommit: true, autoCommit: false, inTransaction: true},
+	}
+	tx := newTx(context.Background(), c)
+	c.tx = tx
+	tx.err = cause
+
+	t.Run("Verify statements fail in an aborted transaction", func(t *testing.T) {
+		_, err := c.ExecContext(context.Background(), "select 1", nil)
+		if !errors.Is(err, ErrTxAborted) {
+			t.Errorf("Unexpected error %v", err)
+		}
+		var e *Error
+		if !errors.As(err, &e) || e.SQLState != "42S02" {
+			t.Errorf("Error does not wrap the cause %v", err)
+		}
+	})
+
+	t.Run("Verify savepoints fail in an aborted transaction", func(t *testing.T) {
+		if err := tx.Savepoint("sp1"); !errors.Is(err, ErrTxAborted) {
+			t.Errorf("Unexpected error %v", err)
+		}
+	})
+
+	t.Run("Verify Commit reports the aborted transaction", func(t *testing.T) {
+		err := tx.Commit()
+		if !errors.Is(err, ErrTxAborted) {
+			t.Errorf("Unexpected error %v", err)
+		}
+		if c.tx != nil {
+			t.Error("Transaction is still active")
+		}
+	})
+}
@@ -0,0 +1,125 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package monetdb
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/MonetDB/MonetDB-Go/v2/mapi"
+)
+
+// The types of the driver implement sql.Scanner. Like string or time.Time,
+// they can not hold a NULL value, scanning NULL into them returns an error.
+// Scan a column that can contain NULL values into a pointer, for example a
+// *monetdb.UUID, which is set to nil for NULL, or into a sql.Null[T] on Go
+// 1.22 and later.
+
+// Decimal is an exact decimal number, the value of a DECIMAL or NUMERIC
+// column. The driver returns DECIMAL values as text, so they can be scanned
+// into a Decimal without loss of precision, but also into a float64 or a
+// string. A Decimal can be used as a query parameter.
+type Decimal = mapi.Decimal
+
+// NewDecimal returns the decimal unscaled * 10^-scale.
+func NewDecimal(unscaled *big.Int, scale int) Decimal {
+	return mapi.NewDecimal(unscaled, scale)
+}
+
+// ParseDecimal parses a decimal number, for example "-123.4500". The scale
+// of the result is the number of digits after the decimal point.
+func ParseDecimal(s string) (Decimal, error) {
+	return mapi.ParseDecimal(s)
+}
+
+// UUID is the value of a uuid column. A UUID or a [16]byte can be used as a
+// query parameter.
+type UUID = mapi.UUID
+
+// ParseUUID parses a UUID in the form "xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx".
+func ParseUUID(s string) (UUID, error) {
+	return mapi.ParseUUID(s)
+}
+
+// Time is the value of a time column, the time of day with the fraction of
+// the second. A Time can be used as a query parameter.
+type Time = mapi.Time
+
+// Date is the value of a date column. A Date can be used as a query
+// parameter.
+type Date = mapi.Date
+
+// MonthInterval is the value of a month_interval column, the number of
+// months of an interval like INTERVAL '1' YEAR. It can be used as a query
+// parameter. The values of sec_interval and day_interval columns are
+// returned as a time.Duration.
+type MonthInterval = mapi.MonthInterval
+
+// Geometry is the value of a geometry, geometrya or mbr column of the GIS
+// module, the well-known text of the shape and its spatial reference
+// system. A Geometry can be used as a query parameter.
+type Geometry = mapi.Geometry
+
+// ParseGeometry parses the well-known text of a shape, for example
+// "SRID=4326;POINT (1 2)".
+func ParseGeometry(s string) (Geometry, error) {
+	return mapi.ParseGeometry(s)
+}
+
+// ParseWKB decodes the well-known binary of a point, line string or
+// polygon.
+func ParseWKB(b []byte) (Geometry, error) {
+	return mapi.ParseWKB(b)
+}
+
+// JSON is a value that is stored in a json column. It is unmarshalled from
+// the json text when it is scanned, and marshalled when it is used as a
+// query parameter:
+//
+//	var event monetdb.JSON[Event]
+//	err := db.QueryRow("select payload from events where id = 1").Scan(&event)
+//	fmt.Println(event.V.Name)
+//
+// Like the other types of the driver, a JSON can not be NULL. Use
+// json.RawMessage to read and write the json text without unmarshalling it.
+type JSON[T any] struct {
+	V T
+}
+
+// Scan implements the sql.Scanner interface.
+func (j *JSON[T]) Scan(src any) error {
+	var v T
+	switch b := src.(type) {
+	case nil:
+		return fmt.Errorf("monetdb: cannot scan NULL into JSON")
+	case []byte:
+		if err := json.Unmarshal(b, &v); err != nil {
+			return err
+		}
+	case json.RawMessage:
+		if err := json.Unmarshal(b, &v); err != nil {
+			return err
+		}
+	case string:
+		if err := json.Unmarshal([]byte(b), &v); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("monetdb: cannot scan %T into JSON", src)
+	}
+	j.V = v
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (j JSON[T]) Value() (driver.Value, error) {
+	b, err := json.Marshal(j.V)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(b), nil
+}
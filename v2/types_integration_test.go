@@ -0,0 +1,799 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package monetdb
+
+import (
+	"database/sql"
+	"encoding/json"
+	"math/big"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecimalIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	db, err := sql.Open("monetdb", "monetdb:monetdb@localhost:50000/monetdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	t.Run("Exec create table", func(t *testing.T) {
+		_, err := db.Exec("create table test_decimal ( id int, amount decimal(18,4) )")
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	values := []string{"12345678901234.5678", "-0.0001", "9007199254740993.0000"}
+
+	t.Run("Exec insert decimals", func(t *testing.T) {
+		stmt, err := db.Prepare("insert into test_decimal values ( ?, ? )")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stmt.Close()
+		for i, v := range values {
+			d, err := ParseDecimal(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := stmt.Exec(i, d); err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+
+	t.Run("Query decimals", func(t *testing.T) {
+		rows, err := db.Query("select amount from test_decimal order by id")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+
+		i := 0
+		for rows.Next() {
+			var d Decimal
+			if err := rows.Scan(&d); err != nil {
+				t.Fatal(err)
+			}
+			if d.String() != values[i] {
+				t.Errorf("Invalid value: %s, expected: %s", d, values[i])
+			}
+			i++
+		}
+		if err := rows.Err(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("Query decimal as float64", func(t *testing.T) {
+		var f float64
+		if err := db.QueryRow("select amount from test_decimal where id = 1").Scan(&f); err != nil {
+			t.Fatal(err)
+		}
+		if f != -0.0001 {
+			t.Errorf("Invalid value: %v, expected: -0.0001", f)
+		}
+	})
+
+	t.Run("Query decimal as string", func(t *testing.T) {
+		rows, err := db.Query("select amount from test_decimal where id = 0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+		types, err := rows.ColumnTypes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if types[0].ScanType() != reflect.TypeOf("") {
+			t.Errorf("Unexpected scan type %v", types[0].ScanType())
+		}
+		if !rows.Next() {
+			t.Fatal(rows.Err())
+		}
+		var s sql.NullString
+		if err := rows.Scan(&s); err != nil {
+			t.Fatal(err)
+		}
+		if s.String != values[0] {
+			t.Errorf("Invalid value: %s, expected: %s", s.String, values[0])
+		}
+	})
+
+	t.Run("Exec drop table", func(t *testing.T) {
+		_, err := db.Exec("drop table test_decimal")
+		if err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestHugeIntIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	db, err := sql.Open("monetdb", "monetdb:monetdb@localhost:50000/monetdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	large, _ := new(big.Int).SetString("170141183460469231731687303715884105727", 10)
+
+	t.Run("Exec create table", func(t *testing.T) {
+		_, err := db.Exec("create table test_hugeint ( id int, value hugeint, small bigint )")
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Exec insert hugeint", func(t *testing.T) {
+		stmt, err := db.Prepare("insert into test_hugeint values ( ?, ?, ? )")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stmt.Close()
+		if _, err := stmt.Exec(1, large, int64(9223372036854775807)); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := stmt.Exec(2, big.NewInt(-5), int64(9223372036854775807)); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Query hugeint", func(t *testing.T) {
+		var v *big.Int
+		if err := db.QueryRow("select value from test_hugeint where id = 1").Scan(&v); err != nil {
+			t.Fatal(err)
+		}
+		if v.Cmp(large) != 0 {
+			t.Errorf("Invalid value: %v, expected: %v", v, large)
+		}
+		var small int64
+		if err := db.QueryRow("select value from test_hugeint where id = 2").Scan(&small); err != nil {
+			t.Fatal(err)
+		}
+		if small != -5 {
+			t.Errorf("Invalid value: %d, expected: -5", small)
+		}
+	})
+
+	t.Run("Query sum over bigint", func(t *testing.T) {
+		rows, err := db.Query("select sum(small) from test_hugeint")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+		types, err := rows.ColumnTypes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if types[0].DatabaseTypeName() == "HUGEINT" && types[0].ScanType() != reflect.TypeOf((*big.Int)(nil)) {
+			t.Errorf("Unexpected scan type %v", types[0].ScanType())
+		}
+		if !rows.Next() {
+			t.Fatal(rows.Err())
+		}
+		var sum *big.Int
+		if err := rows.Scan(&sum); err != nil {
+			t.Fatal(err)
+		}
+		if sum.String() != "18446744073709551614" {
+			t.Errorf("Invalid value: %v, expected: 18446744073709551614", sum)
+		}
+	})
+
+	t.Run("Exec drop table", func(t *testing.T) {
+		_, err := db.Exec("drop table test_hugeint")
+		if err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestUUIDIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	db, err := sql.Open("monetdb", "monetdb:monetdb@localhost:50000/monetdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	id, err := ParseUUID("6a6d3e4e-1f2b-4c3d-9e8f-0a1b2c3d4e5f")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Exec create table", func(t *testing.T) {
+		_, err := db.Exec("create table test_uuid ( id uuid, name varchar(16) )")
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Exec insert uuid", func(t *testing.T) {
+		stmt, err := db.Prepare("insert into test_uuid values ( ?, ? )")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stmt.Close()
+		if _, err := stmt.Exec(id, "uuid"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := stmt.Exec([16]byte{1}, "array"); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Query uuid", func(t *testing.T) {
+		rows, err := db.Query("select id from test_uuid where name = 'uuid'")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+		types, err := rows.ColumnTypes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if types[0].DatabaseTypeName() != "UUID" || types[0].ScanType() != reflect.TypeOf(UUID{}) {
+			t.Errorf("Unexpected column type %s %v", types[0].DatabaseTypeName(), types[0].ScanType())
+		}
+		if !rows.Next() {
+			t.Fatal(rows.Err())
+		}
+		var u UUID
+		if err := rows.Scan(&u); err != nil {
+			t.Fatal(err)
+		}
+		if u != id {
+			t.Errorf("Invalid value: %s, expected: %s", u, id)
+		}
+	})
+
+	t.Run("Query with uuid parameter", func(t *testing.T) {
+		stmt, err := db.Prepare("select name from test_uuid where id = ?")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stmt.Close()
+		var name string
+		if err := stmt.QueryRow([16]byte{1}).Scan(&name); err != nil {
+			t.Fatal(err)
+		}
+		if name != "array" {
+			t.Errorf("Invalid value: %s, expected: array", name)
+		}
+	})
+
+	t.Run("Exec drop table", func(t *testing.T) {
+		_, err := db.Exec("drop table test_uuid")
+		if err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestJSONIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	db, err := sql.Open("monetdb", "monetdb:monetdb@localhost:50000/monetdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	type event struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}
+
+	t.Run("Exec create table", func(t *testing.T) {
+		_, err := db.Exec("create table test_json ( id int, payload json )")
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Exec insert json", func(t *testing.T) {
+		stmt, err := db.Prepare("insert into test_json values ( ?, ? )")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stmt.Close()
+		if _, err := stmt.Exec(1, JSON[event]{V: event{Name: "it's \"quoted\"", Count: 2}}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := stmt.Exec(2, json.RawMessage(`[1, 2, 3]`)); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := stmt.Exec(3, nil); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Query json", func(t *testing.T) {
+		rows, err := db.Query("select payload from test_json where id = 1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+		types, err := rows.ColumnTypes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if types[0].DatabaseTypeName() != "JSON" || types[0].ScanType() != reflect.TypeOf("") {
+			t.Errorf("Unexpected column type %s %v", types[0].DatabaseTypeName(), types[0].ScanType())
+		}
+		if !rows.Next() {
+			t.Fatal(rows.Err())
+		}
+		var e JSON[event]
+		if err := rows.Scan(&e); err != nil {
+			t.Fatal(err)
+		}
+		if e.V.Name != "it's \"quoted\"" || e.V.Count != 2 {
+			t.Errorf("Invalid value: %+v", e.V)
+		}
+	})
+
+	t.Run("Query json as raw message", func(t *testing.T) {
+		var raw json.RawMessage
+		if err := db.QueryRow("select payload from test_json where id = 2").Scan(&raw); err != nil {
+			t.Fatal(err)
+		}
+		var values []int
+		if err := json.Unmarshal(raw, &values); err != nil {
+			t.Fatal(err)
+		}
+		if len(values) != 3 || values[2] != 3 {
+			t.Errorf("Invalid value: %s", raw)
+		}
+	})
+
+	t.Run("Query json as string", func(t *testing.T) {
+		var s string
+		if err := db.QueryRow("select payload from test_json where id = 2").Scan(&s); err != nil {
+			t.Fatal(err)
+		}
+		var values []int
+		if err := json.Unmarshal([]byte(s), &values); err != nil {
+			t.Fatal(err)
+		}
+		if len(values) != 3 || values[2] != 3 {
+			t.Errorf("Invalid value: %s", s)
+		}
+		var ns sql.NullString
+		if err := db.QueryRow("select payload from test_json where id = 3").Scan(&ns); err != nil {
+			t.Fatal(err)
+		}
+		if ns.Valid {
+			t.Errorf("Invalid value: %v, expected NULL", ns)
+		}
+	})
+
+	t.Run("Query null json", func(t *testing.T) {
+		var e JSON[event]
+		if err := db.QueryRow("select payload from test_json where id = 3").Scan(&e); err == nil {
+			t.Error("Scanning NULL did not fail")
+		}
+		p := &JSON[event]{}
+		if err := db.QueryRow("select payload from test_json where id = 3").Scan(&p); err != nil {
+			t.Fatal(err)
+		}
+		if p != nil {
+			t.Errorf("Invalid value: %+v, expected nil", p)
+		}
+	})
+
+	t.Run("Exec drop table", func(t *testing.T) {
+		_, err := db.Exec("drop table test_json")
+		if err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestNetworkTypesIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	db, err := sql.Open("monetdb", "monetdb:monetdb@localhost:50000/monetdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	host := netip.MustParseAddr("192.168.1.5")
+	network := netip.MustParsePrefix("10.0.0.0/8")
+	link, err := url.Parse("https://example.com/status?check=it's")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Exec create table", func(t *testing.T) {
+		_, err := db.Exec("create table test_network ( id int, addr inet, link url )")
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Exec insert inet and url", func(t *testing.T) {
+		stmt, err := db.Prepare("insert into test_network values ( ?, ?, ? )")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stmt.Close()
+		if _, err := stmt.Exec(1, host, link); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := stmt.Exec(2, network, nil); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Query inet and url", func(t *testing.T) {
+		rows, err := db.Query("select addr, link from test_network order by id")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+		types, err := rows.ColumnTypes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if types[0].DatabaseTypeName() != "INET" || types[0].ScanType() != reflect.TypeOf(netip.Prefix{}) {
+			t.Errorf("Unexpected column type %s %v", types[0].DatabaseTypeName(), types[0].ScanType())
+		}
+		if types[1].DatabaseTypeName() != "URL" || types[1].ScanType() != reflect.TypeOf((*url.URL)(nil)) {
+			t.Errorf("Unexpected column type %s %v", types[1].DatabaseTypeName(), types[1].ScanType())
+		}
+
+		var addr netip.Prefix
+		var u *url.URL
+		if !rows.Next() {
+			t.Fatal(rows.Err())
+		}
+		if err := rows.Scan(&addr, &u); err != nil {
+			t.Fatal(err)
+		}
+		if !addr.IsSingleIP() || addr.Addr() != host {
+			t.Errorf("Invalid value: %v, expected: %v", addr, host)
+		}
+		if u == nil || u.String() != link.String() {
+			t.Errorf("Invalid value: %v, expected: %v", u, link)
+		}
+
+		if !rows.Next() {
+			t.Fatal(rows.Err())
+		}
+		if err := rows.Scan(&addr, &u); err != nil {
+			t.Fatal(err)
+		}
+		if addr != network {
+			t.Errorf("Invalid value: %v, expected: %v", addr, network)
+		}
+		if u != nil {
+			t.Errorf("Invalid value: %v, expected: nil", u)
+		}
+	})
+
+	t.Run("Exec drop table", func(t *testing.T) {
+		_, err := db.Exec("drop table test_network")
+		if err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestTemporalIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	db, err := sql.Open("monetdb", "monetdb:monetdb@localhost:50000/monetdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	zone := time.FixedZone("", -(3*3600 + 1800))
+	stamp := time.Date(2024, time.February, 29, 23, 59, 58, 123456000, zone)
+	clock := Time{Hour: 23, Min: 59, Sec: 58, Nsec: 123456000}
+
+	t.Run("Exec create table", func(t *testing.T) {
+		_, err := db.Exec("create table test_temporal ( ts timestamp(6), tstz timestamptz(6), tm time(6), tmtz timetz(6) )")
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Exec insert temporal values", func(t *testing.T) {
+		stmt, err := db.Prepare("insert into test_temporal values ( ?, ?, ?, ? )")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stmt.Close()
+		if _, err := stmt.Exec(stamp.UTC(), stamp, clock, "23:59:58.123456-03:30"); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Query temporal values", func(t *testing.T) {
+		rows, err := db.Query("select ts, tstz, tm, tmtz from test_temporal")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+		types, err := rows.ColumnTypes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if types[2].DatabaseTypeName() != "TIME" || types[2].ScanType() != reflect.TypeOf(Time{}) {
+			t.Errorf("Unexpected column type %s %v", types[2].DatabaseTypeName(), types[2].ScanType())
+		}
+		if types[3].DatabaseTypeName() != "TIMETZ" || types[3].ScanType() != reflect.TypeOf(time.Time{}) {
+			t.Errorf("Unexpected column type %s %v", types[3].DatabaseTypeName(), types[3].ScanType())
+		}
+		if !rows.Next() {
+			t.Fatal(rows.Err())
+		}
+		var ts, tstz, tmtz time.Time
+		var tm Time
+		if err := rows.Scan(&ts, &tstz, &tm, &tmtz); err != nil {
+			t.Fatal(err)
+		}
+		// A timestamp has no time zone, the server converts the value to
+		// the time zone of the session
+		if ts.Nanosecond() != stamp.Nanosecond() {
+			t.Errorf("Invalid timestamp: %v, expected the fraction of: %v", ts, stamp)
+		}
+		if !tstz.Equal(stamp) || tstz.Nanosecond() != stamp.Nanosecond() {
+			t.Errorf("Invalid timestamptz: %v, expected: %v", tstz, stamp)
+		}
+		if tm != clock {
+			t.Errorf("Invalid time: %v, expected: %v", tm, clock)
+		}
+		if tmtz.Nanosecond() != clock.Nsec || !tmtz.Equal(time.Date(1970, time.January, 1, 23, 59, 58, 123456000, zone)) {
+			t.Errorf("Invalid timetz: %v", tmtz)
+		}
+	})
+
+	t.Run("Query with nanoseconds", func(t *testing.T) {
+		stmt, err := db.Prepare("select cast(? as timestamptz(6))")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stmt.Close()
+		var v time.Time
+		if err := stmt.QueryRow(stamp.Add(789)).Scan(&v); err != nil {
+			t.Fatal(err)
+		}
+		// The fraction is truncated to microseconds
+		if !v.Equal(stamp) {
+			t.Errorf("Invalid value: %v, expected: %v", v, stamp)
+		}
+	})
+
+	t.Run("Query date", func(t *testing.T) {
+		rows, err := db.Query("select cast('2024-02-29' as date)")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+		types, err := rows.ColumnTypes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if types[0].ScanType() != reflect.TypeOf(Date{}) {
+			t.Errorf("Unexpected scan type %v", types[0].ScanType())
+		}
+		if !rows.Next() {
+			t.Fatal(rows.Err())
+		}
+		var d Date
+		if err := rows.Scan(&d); err != nil {
+			t.Fatal(err)
+		}
+		if d != (Date{Year: 2024, Month: time.February, Day: 29}) {
+			t.Errorf("Invalid value: %v", d)
+		}
+	})
+
+	t.Run("Exec drop table", func(t *testing.T) {
+		_, err := db.Exec("drop table test_temporal")
+		if err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestIntervalIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	db, err := sql.Open("monetdb", "monetdb:monetdb@localhost:50000/monetdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	t.Run("Exec create table", func(t *testing.T) {
+		_, err := db.Exec("create table test_interval ( s interval second, d interval day, m interval month )")
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Exec insert intervals", func(t *testing.T) {
+		stmt, err := db.Prepare("insert into test_interval values ( ?, ?, ? )")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stmt.Close()
+		if _, err := stmt.Exec(90*time.Minute+250*time.Millisecond, 72*time.Hour, MonthInterval(14)); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Query intervals", func(t *testing.T) {
+		rows, err := db.Query("select s, d, m from test_interval")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+		types, err := rows.ColumnTypes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, e := range []reflect.Type{reflect.TypeOf(time.Duration(0)), reflect.TypeOf(time.Duration(0)), reflect.TypeOf(MonthInterval(0))} {
+			if types[i].ScanType() != e {
+				t.Errorf("Unexpected scan type %s %v, expected: %v", types[i].DatabaseTypeName(), types[i].ScanType(), e)
+			}
+		}
+		if !rows.Next() {
+			t.Fatal(rows.Err())
+		}
+		var s, d time.Duration
+		var m MonthInterval
+		if err := rows.Scan(&s, &d, &m); err != nil {
+			t.Fatal(err)
+		}
+		if s != 90*time.Minute+250*time.Millisecond {
+			t.Errorf("Invalid value: %v", s)
+		}
+		if d != 72*time.Hour {
+			t.Errorf("Invalid value: %v", d)
+		}
+		if m != 14 {
+			t.Errorf("Invalid value: %v", m)
+		}
+	})
+
+	t.Run("Query interval arithmetic", func(t *testing.T) {
+		var d time.Duration
+		if err := db.QueryRow("select interval '2' hour + interval '30' second").Scan(&d); err != nil {
+			t.Fatal(err)
+		}
+		if d != 2*time.Hour+30*time.Second {
+			t.Errorf("Invalid value: %v", d)
+		}
+	})
+
+	t.Run("Exec drop table", func(t *testing.T) {
+		_, err := db.Exec("drop table test_interval")
+		if err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestGeometryIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	db, err := sql.Open("monetdb", "monetdb:monetdb@localhost:50000/monetdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var available bool
+	if err := db.QueryRow("select count(*) > 0 from sys.functions where name = 'st_geomfromtext'").Scan(&available); err != nil {
+		t.Fatal(err)
+	}
+	if !available {
+		t.Skip("the server has no GIS module")
+	}
+
+	shape := Geometry{WKT: "LINESTRING (0 0, 1.5 2.5)"}
+
+	t.Run("Exec create table", func(t *testing.T) {
+		_, err := db.Exec("create table test_geometry ( id int, shape geometry )")
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Exec insert geometry", func(t *testing.T) {
+		stmt, err := db.Prepare("insert into test_geometry values ( ?, ? )")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stmt.Close()
+		if _, err := stmt.Exec(1, shape); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Query geometry", func(t *testing.T) {
+		rows, err := db.Query("select shape, mbr(shape) from test_geometry where id = 1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+		types, err := rows.ColumnTypes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, ct := range types {
+			if ct.ScanType() != reflect.TypeOf(Geometry{}) {
+				t.Errorf("Unexpected column type %s %v", ct.DatabaseTypeName(), ct.ScanType())
+			}
+		}
+		if !rows.Next() {
+			t.Fatal(rows.Err())
+		}
+		var g, box Geometry
+		if err := rows.Scan(&g, &box); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(strings.ToUpper(g.WKT), "LINESTRING") {
+			t.Errorf("Invalid value: %v, expected: %v", g, shape)
+		}
+		if !strings.HasPrefix(strings.ToUpper(box.WKT), "BOX") {
+			t.Errorf("Invalid value: %v, expected a box", box)
+		}
+	})
+
+	t.Run("Query with geometry parameter", func(t *testing.T) {
+		stmt, err := db.Prepare("select count(*) from test_geometry where st_equals(shape, ?)")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stmt.Close()
+		var n int
+		if err := stmt.QueryRow(shape).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		if n != 1 {
+			t.Errorf("Invalid count: %d, expected: 1", n)
+		}
+	})
+
+	t.Run("Exec drop table", func(t *testing.T) {
+		_, err := db.Exec("drop table test_geometry")
+		if err != nil {
+			t.Error(err)
+		}
+	})
+}
@@ -0,0 +1,71 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package monetdb
+
+import (
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"github.com/MonetDB/MonetDB-Go/v2/mapi"
+)
+
+type testEvent struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func TestJSON(t *testing.T) {
+	t.Run("Verify Scan", func(t *testing.T) {
+		for _, src := range []any{
+			[]byte(`{"name": "start", "count": 2}`),
+			json.RawMessage(`{"name": "start", "count": 2}`),
+			`{"name": "start", "count": 2}`,
+		} {
+			var v JSON[testEvent]
+			if err := v.Scan(src); err != nil {
+				t.Fatal(err)
+			}
+			if v.V.Name != "start" || v.V.Count != 2 {
+				t.Errorf("Unexpected value %+v", v.V)
+			}
+		}
+
+		v := JSON[testEvent]{V: testEvent{Name: "old"}}
+		if err := v.Scan(nil); err == nil || v.V.Name != "old" {
+			t.Errorf("Scanning NULL did not fail, value %+v", v.V)
+		}
+		if err := v.Scan([]byte("{")); err == nil {
+			t.Error("Scanning invalid json did not fail")
+		}
+	})
+
+	t.Run("Verify parameter", func(t *testing.T) {
+		v := JSON[testEvent]{V: testEvent{Name: "it's", Count: 1}}
+		s, err := mapi.ConvertToMonet(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != `'{"name":"it\'s","count":1}'` {
+			t.Errorf("Invalid value: %s", s)
+		}
+	})
+}
+
+func TestScanNull(t *testing.T) {
+	// None of the types of the driver can hold NULL
+	scanners := []sql.Scanner{
+		&Decimal{},
+		&UUID{},
+		&Geometry{},
+		new(MonthInterval),
+		&JSON[testEvent]{},
+	}
+	for _, s := range scanners {
+		if err := s.Scan(nil); err == nil {
+			t.Errorf("Scanning NULL into %T did not fail", s)
+		}
+	}
+}